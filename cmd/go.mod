@@ -0,0 +1,24 @@
+module github.com/mehdihadeli/go-mediatr/cmd
+
+go 1.24.0
+
+replace (
+	github.com/mehdihadeli/go-mediatr => ../
+	github.com/mehdihadeli/go-mediatr/mediatrcheck => ../mediatrcheck
+)
+
+require (
+	github.com/mehdihadeli/go-mediatr v0.0.0-00010101000000-000000000000 // imported by the testdata of mediatr-flow
+	github.com/mehdihadeli/go-mediatr/mediatrcheck v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	golang.org/x/tools v0.42.0
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/mod v0.33.0 // indirect
+	golang.org/x/sync v0.19.0 // indirect
+)
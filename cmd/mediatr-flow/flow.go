@@ -0,0 +1,335 @@
+package main
+
+import (
+	"go/ast"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const mediatrPath = "github.com/mehdihadeli/go-mediatr"
+
+// Node kinds
+const (
+	kindRequest      = "request"
+	kindNotification = "notification"
+	kindFunction     = "function"
+)
+
+// Edge kinds
+const (
+	edgeSend    = "send"
+	edgePublish = "publish"
+	edgeHandle  = "handle"
+)
+
+// Graph is the message flow of a set of packages: the functions sending requests and publishing notifications,
+// and the handlers of the messages.
+type Graph struct {
+	Nodes []*Node `json:"nodes"`
+	Edges []Edge  `json:"edges"`
+	// Cycles are the sets of nodes where messages flow back to themselves
+	Cycles [][]string `json:"cycles"`
+	// OrphanMessages are the messages sent or published without any handler
+	OrphanMessages []string `json:"orphanMessages"`
+	// UnusedHandlers are the handlers of messages that are never sent or published
+	UnusedHandlers []string `json:"unusedHandlers"`
+}
+
+// Node is a message type, or a function sending, publishing or handling messages. The ID is qualified by the
+// import path of the package, the label by its name.
+type Node struct {
+	ID       string `json:"id"`
+	Label    string `json:"label"`
+	Kind     string `json:"kind"`
+	Position string `json:"position,omitempty"`
+}
+
+// Edge is a function sending or publishing a message, or a message handled by a function.
+type Edge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+	Kind string `json:"kind"`
+}
+
+// graphBuilder collects the nodes and edges found in the packages
+type graphBuilder struct {
+	nodes map[string]*Node
+	edges map[Edge]bool
+}
+
+// buildGraph finds the Send, SendAsync and Publish calls of the packages, not of their dependencies, and the Handle methods of their types:
+// methods named Handle or HandleX with a `(context.Context, TRequest) (TResponse, error)` or
+// `(context.Context, TNotification) error` signature
+func buildGraph(pkgs []*packages.Package) *Graph {
+	builder := &graphBuilder{nodes: map[string]*Node{}, edges: map[Edge]bool{}}
+
+	for _, pkg := range pkgs {
+		if pkg.Types == nil || pkg.TypesInfo == nil || pkg.PkgPath == mediatrPath {
+			continue
+		}
+		builder.addHandlers(pkg)
+		builder.addDispatches(pkg)
+	}
+
+	return builder.graph()
+}
+
+func (b *graphBuilder) addHandlers(pkg *packages.Package) {
+	scope := pkg.Types.Scope()
+	for _, name := range scope.Names() {
+		typeName, ok := scope.Lookup(name).(*types.TypeName)
+		if !ok || typeName.IsAlias() {
+			continue
+		}
+		named, ok := typeName.Type().(*types.Named)
+		if !ok || named.TypeParams().Len() > 0 {
+			continue
+		}
+
+		for method := range named.Methods() {
+			if !strings.HasPrefix(method.Name(), "Handle") {
+				continue
+			}
+			messageType, kind, ok := handledMessage(method.Type().(*types.Signature))
+			if !ok {
+				continue
+			}
+
+			handler := b.addFunction(method, pkg.Fset.Position(method.Pos()).String())
+			message := b.addMessage(messageType, kind)
+			b.edges[Edge{From: message.ID, To: handler.ID, Kind: edgeHandle}] = true
+		}
+	}
+}
+
+func (b *graphBuilder) addDispatches(pkg *packages.Package) {
+	for _, file := range pkg.Syntax {
+		for _, decl := range file.Decls {
+			funcDecl, ok := decl.(*ast.FuncDecl)
+			if !ok || funcDecl.Body == nil {
+				continue
+			}
+			function, ok := pkg.TypesInfo.Defs[funcDecl.Name].(*types.Func)
+			if !ok {
+				continue
+			}
+
+			ast.Inspect(funcDecl.Body, func(node ast.Node) bool {
+				ident, ok := node.(*ast.Ident)
+				if !ok {
+					return true
+				}
+				instance, ok := pkg.TypesInfo.Instances[ident]
+				if !ok || instance.TypeArgs.Len() == 0 {
+					return true
+				}
+				callee, ok := pkg.TypesInfo.Uses[ident].(*types.Func)
+				if !ok || callee.Pkg() == nil || callee.Pkg().Path() != mediatrPath {
+					return true
+				}
+
+				var kind, edgeKind string
+				switch callee.Name() {
+				case "Send", "SendAsync":
+					kind, edgeKind = kindRequest, edgeSend
+				case "Publish":
+					kind, edgeKind = kindNotification, edgePublish
+				default:
+					return true
+				}
+
+				sender := b.addFunction(function, pkg.Fset.Position(funcDecl.Name.Pos()).String())
+				message := b.addMessage(instance.TypeArgs.At(0), kind)
+				b.edges[Edge{From: sender.ID, To: message.ID, Kind: edgeKind}] = true
+
+				return true
+			})
+		}
+	}
+}
+
+func (b *graphBuilder) addFunction(function *types.Func, position string) *Node {
+	return b.addNode(funcName(function, packagePath), funcName(function, packageName), kindFunction, position)
+}
+
+func (b *graphBuilder) addMessage(messageType types.Type, kind string) *Node {
+	return b.addNode(types.TypeString(messageType, packagePath), types.TypeString(messageType, packageName), kind, "")
+}
+
+func (b *graphBuilder) addNode(id string, label string, kind string, position string) *Node {
+	node, ok := b.nodes[id]
+	if !ok {
+		node = &Node{ID: id, Label: label, Kind: kind}
+		b.nodes[id] = node
+	}
+	if node.Position == "" {
+		node.Position = position
+	}
+
+	return node
+}
+
+func (b *graphBuilder) graph() *Graph {
+	graph := &Graph{Cycles: [][]string{}, OrphanMessages: []string{}, UnusedHandlers: []string{}}
+
+	for _, node := range b.nodes {
+		graph.Nodes = append(graph.Nodes, node)
+	}
+	slices.SortFunc(graph.Nodes, func(a, b *Node) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+
+	for edge := range b.edges {
+		graph.Edges = append(graph.Edges, edge)
+	}
+	slices.SortFunc(graph.Edges, func(a, b Edge) int {
+		if c := strings.Compare(a.From, b.From); c != 0 {
+			return c
+		}
+		return strings.Compare(a.To, b.To)
+	})
+
+	handled := map[string]bool{}
+	dispatched := map[string]bool{}
+	for _, edge := range graph.Edges {
+		if edge.Kind == edgeHandle {
+			handled[edge.From] = true
+		} else {
+			dispatched[edge.To] = true
+		}
+	}
+	for _, node := range graph.Nodes {
+		if node.Kind != kindFunction && !handled[node.ID] {
+			graph.OrphanMessages = append(graph.OrphanMessages, node.ID)
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.Kind == edgeHandle && !dispatched[edge.From] {
+			graph.UnusedHandlers = append(graph.UnusedHandlers, edge.To)
+		}
+	}
+
+	graph.Cycles = findCycles(graph)
+
+	return graph
+}
+
+// findCycles returns the strongly connected components of the graph with more than one node, or with a node
+// linked to itself, using Tarjan's algorithm
+func findCycles(graph *Graph) [][]string {
+	successors := map[string][]string{}
+	for _, edge := range graph.Edges {
+		successors[edge.From] = append(successors[edge.From], edge.To)
+	}
+
+	index := 0
+	indexes := map[string]int{}
+	lowLinks := map[string]int{}
+	onStack := map[string]bool{}
+	var stack []string
+	cycles := [][]string{}
+
+	var connect func(id string)
+	connect = func(id string) {
+		indexes[id] = index
+		lowLinks[id] = index
+		index++
+		stack = append(stack, id)
+		onStack[id] = true
+
+		for _, successor := range successors[id] {
+			if _, visited := indexes[successor]; !visited {
+				connect(successor)
+				lowLinks[id] = min(lowLinks[id], lowLinks[successor])
+			} else if onStack[successor] {
+				lowLinks[id] = min(lowLinks[id], indexes[successor])
+			}
+		}
+
+		if lowLinks[id] != indexes[id] {
+			return
+		}
+
+		var component []string
+		for {
+			last := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			onStack[last] = false
+			component = append(component, last)
+			if last == id {
+				break
+			}
+		}
+		if len(component) > 1 || slices.Contains(successors[id], id) {
+			slices.Sort(component)
+			cycles = append(cycles, component)
+		}
+	}
+
+	for _, node := range graph.Nodes {
+		if _, visited := indexes[node.ID]; !visited {
+			connect(node.ID)
+		}
+	}
+	slices.SortFunc(cycles, func(a, b []string) int {
+		return strings.Compare(a[0], b[0])
+	})
+
+	return cycles
+}
+
+// handledMessage returns the message type handled by a method with a handler signature
+func handledMessage(signature *types.Signature) (types.Type, string, bool) {
+	params, results := signature.Params(), signature.Results()
+	if signature.Variadic() || params.Len() != 2 || !isContext(params.At(0).Type()) {
+		return nil, "", false
+	}
+
+	switch {
+	case results.Len() == 1 && isError(results.At(0).Type()):
+		return params.At(1).Type(), kindNotification, true
+	case results.Len() == 2 && isError(results.At(1).Type()):
+		return params.At(1).Type(), kindRequest, true
+	default:
+		return nil, "", false
+	}
+}
+
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// funcName returns the name of a function with its package qualified by qualifier, e.g.
+// "(*commands.CreateProductCommandHandler).Handle"
+func funcName(function *types.Func, qualifier types.Qualifier) string {
+	signature := function.Type().(*types.Signature)
+	if signature.Recv() == nil {
+		return qualifier(function.Pkg()) + "." + function.Name()
+	}
+
+	receiver := signature.Recv().Type()
+	if pointer, ok := receiver.(*types.Pointer); ok {
+		return "(*" + types.TypeString(pointer.Elem(), qualifier) + ")." + function.Name()
+	}
+
+	return types.TypeString(receiver, qualifier) + "." + function.Name()
+}
+
+// packagePath qualifies the node IDs, so that packages with the same name don't collide
+func packagePath(pkg *types.Package) string {
+	return pkg.Path()
+}
+
+// packageName qualifies the node labels
+func packageName(pkg *types.Package) string {
+	return pkg.Name()
+}
@@ -0,0 +1,62 @@
+// Command mediatr-flow prints the message flow of a codebase: the functions sending requests and publishing
+// notifications with mediatr, and the handlers of the messages. The graph is found statically, across the
+// packages matching the patterns, and lists the cycles, the messages without handler and the handlers of
+// messages that are never sent.
+//
+// Usage:
+//
+//	mediatr-flow [-format dot|json] [packages]
+//
+// Example:
+//
+//	go run github.com/mehdihadeli/go-mediatr/cmd/mediatr-flow ./... | dot -Tsvg > flow.svg
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	format := flag.String("format", "dot", "output format, dot or json")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: mediatr-flow [-format dot|json] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(os.Stdout, *format, flag.Args()); err != nil {
+		fmt.Fprintf(os.Stderr, "mediatr-flow: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(w io.Writer, format string, patterns []string) error {
+	if format != "dot" && format != "json" {
+		return fmt.Errorf("unknown format %q, expected dot or json", format)
+	}
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+
+	pkgs, err := packages.Load(&packages.Config{
+		Mode: packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+	}, patterns...)
+	if err != nil {
+		return err
+	}
+	if packages.PrintErrors(pkgs) > 0 {
+		return fmt.Errorf("packages contain errors")
+	}
+
+	graph := buildGraph(pkgs)
+	if format == "json" {
+		return writeJSON(w, graph)
+	}
+
+	return writeDOT(w, graph)
+}
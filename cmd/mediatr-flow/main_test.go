@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	shop       = "github.com/mehdihadeli/go-mediatr/cmd/mediatr-flow/testdata/shop"
+	legacyShop = "github.com/mehdihadeli/go-mediatr/cmd/mediatr-flow/testdata/legacy/shop"
+)
+
+func Test_Run_Should_Find_Message_Flow_Cycles_And_Orphans(t *testing.T) {
+	var output bytes.Buffer
+
+	err := run(&output, "json", []string{"./testdata/shop"})
+
+	require.NoError(t, err)
+	var graph Graph
+	require.NoError(t, json.Unmarshal(output.Bytes(), &graph))
+	assert.Contains(t, graph.Edges, Edge{From: shop + ".Checkout", To: "*" + shop + ".PlaceOrder", Kind: "send"})
+	assert.Contains(t, graph.Edges, Edge{From: "(*" + shop + ".PlaceOrderHandler).Handle", To: "*" + shop + ".OrderPlaced", Kind: "publish"})
+	assert.Contains(t, graph.Edges, Edge{From: "*" + shop + ".ReserveStock", To: shop + ".StockHandlers.HandleReserve", Kind: "handle"})
+	assert.Equal(t, [][]string{{
+		"(*" + shop + ".OrderPlacedHandler).Handle",
+		"(*" + shop + ".PlaceOrderHandler).Handle",
+		"*" + shop + ".OrderPlaced",
+		"*" + shop + ".PlaceOrder",
+		"*" + shop + ".ReserveStock",
+		"*" + shop + ".StockReserved",
+		shop + ".StockHandlers.HandleReserve",
+		shop + ".StockHandlers.HandleStockReserved",
+	}}, graph.Cycles)
+	assert.Equal(t, []string{"*" + shop + ".CancelOrder"}, graph.OrphanMessages)
+	assert.Equal(t, []string{"(*" + shop + ".AuditHandler).Handle"}, graph.UnusedHandlers)
+}
+
+func Test_Run_Should_Write_DOT(t *testing.T) {
+	var output bytes.Buffer
+
+	err := run(&output, "dot", []string{"./testdata/shop"})
+
+	require.NoError(t, err)
+	dot := output.String()
+	assert.True(t, strings.HasPrefix(dot, "digraph mediatr_flow {\n"))
+	assert.Contains(t, dot, `"*`+shop+`.CancelOrder" [label="*shop.CancelOrder", shape=ellipse, style=dashed, color=red];`)
+	assert.Contains(t, dot, `"`+shop+`.Checkout" -> "*`+shop+`.PlaceOrder" [label="send"];`)
+	assert.Contains(t, dot, `"*`+shop+`.PlaceOrder" -> "(*`+shop+`.PlaceOrderHandler).Handle" [label="handle", color=red];`)
+}
+
+func Test_Run_Should_Not_Merge_Packages_With_The_Same_Name(t *testing.T) {
+	var output bytes.Buffer
+
+	err := run(&output, "json", []string{"./testdata/shop", "./testdata/legacy/shop"})
+
+	require.NoError(t, err)
+	var graph Graph
+	require.NoError(t, json.Unmarshal(output.Bytes(), &graph))
+	assert.Contains(t, graph.Nodes, &Node{ID: "*" + legacyShop + ".PlaceOrder", Label: "*shop.PlaceOrder", Kind: "request"})
+	assert.Contains(t, graph.Nodes, &Node{ID: "*" + shop + ".PlaceOrder", Label: "*shop.PlaceOrder", Kind: "request"})
+	assert.Contains(t, graph.Edges, Edge{From: legacyShop + ".Checkout", To: "*" + legacyShop + ".PlaceOrder", Kind: "send"})
+	assert.NotContains(t, graph.Edges, Edge{From: legacyShop + ".Checkout", To: "*" + shop + ".PlaceOrder", Kind: "send"})
+	assert.Len(t, graph.Cycles, 1, "the handler of the other package should not join the cycle")
+	assert.NotContains(t, graph.Cycles[0], "(*"+legacyShop+".PlaceOrderHandler).Handle")
+}
+
+func Test_Run_Should_Return_Error_For_Unknown_Format(t *testing.T) {
+	err := run(&bytes.Buffer{}, "svg", nil)
+
+	assert.EqualError(t, err, `unknown format "svg", expected dot or json`)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// writeJSON writes the graph as indented JSON
+func writeJSON(w io.Writer, graph *Graph) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+
+	return encoder.Encode(graph)
+}
+
+// writeDOT writes the graph as a Graphviz digraph, with the orphan messages, the unused handlers
+// and the nodes of cycles drawn in red
+func writeDOT(w io.Writer, graph *Graph) error {
+	flagged := map[string]bool{}
+	for _, id := range graph.OrphanMessages {
+		flagged[id] = true
+	}
+	for _, id := range graph.UnusedHandlers {
+		flagged[id] = true
+	}
+	inCycle := map[string]bool{}
+	for _, cycle := range graph.Cycles {
+		for _, id := range cycle {
+			inCycle[id] = true
+		}
+	}
+
+	var builder strings.Builder
+	builder.WriteString("digraph mediatr_flow {\n")
+	builder.WriteString("\trankdir=LR;\n")
+
+	for _, node := range graph.Nodes {
+		attributes := []string{"label=" + quote(node.Label)}
+		switch node.Kind {
+		case kindRequest:
+			attributes = append(attributes, "shape=ellipse")
+		case kindNotification:
+			attributes = append(attributes, "shape=hexagon")
+		default:
+			attributes = append(attributes, "shape=box")
+		}
+		if flagged[node.ID] {
+			attributes = append(attributes, "style=dashed", "color=red")
+		} else if inCycle[node.ID] {
+			attributes = append(attributes, "color=red")
+		}
+		fmt.Fprintf(&builder, "\t%s [%s];\n", quote(node.ID), strings.Join(attributes, ", "))
+	}
+
+	for _, edge := range graph.Edges {
+		attributes := []string{"label=" + quote(edge.Kind)}
+		if inCycle[edge.From] && inCycle[edge.To] {
+			attributes = append(attributes, "color=red")
+		}
+		fmt.Fprintf(&builder, "\t%s -> %s [%s];\n", quote(edge.From), quote(edge.To), strings.Join(attributes, ", "))
+	}
+
+	builder.WriteString("}\n")
+
+	_, err := io.WriteString(w, builder.String())
+
+	return err
+}
+
+func quote(label string) string {
+	label = strings.ReplaceAll(label, `\`, `\\`)
+
+	return `"` + strings.ReplaceAll(label, `"`, `\"`) + `"`
+}
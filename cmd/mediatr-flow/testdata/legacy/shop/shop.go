@@ -0,0 +1,20 @@
+package shop
+
+import (
+	"context"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+type PlaceOrder struct{}
+
+type PlaceOrderHandler struct{}
+
+func (h *PlaceOrderHandler) Handle(ctx context.Context, command *PlaceOrder) (mediatr.Unit, error) {
+	return mediatr.Unit{}, nil
+}
+
+func Checkout(ctx context.Context) error {
+	_, err := mediatr.Send[*PlaceOrder, mediatr.Unit](ctx, &PlaceOrder{})
+	return err
+}
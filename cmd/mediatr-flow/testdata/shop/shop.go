@@ -0,0 +1,57 @@
+package shop
+
+import (
+	"context"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+type PlaceOrder struct{}
+
+type OrderPlaced struct{}
+
+type ReserveStock struct{}
+
+type StockReserved struct{}
+
+type CancelOrder struct{}
+
+type Audit struct{}
+
+type PlaceOrderHandler struct{}
+
+func (h *PlaceOrderHandler) Handle(ctx context.Context, command *PlaceOrder) (mediatr.Unit, error) {
+	return mediatr.Unit{}, mediatr.Publish(ctx, &OrderPlaced{})
+}
+
+type OrderPlacedHandler struct{}
+
+func (h *OrderPlacedHandler) Handle(ctx context.Context, event *OrderPlaced) error {
+	_, err := mediatr.Send[*ReserveStock, mediatr.Unit](ctx, &ReserveStock{})
+	return err
+}
+
+type StockHandlers struct{}
+
+func (h StockHandlers) HandleReserve(ctx context.Context, command *ReserveStock) (mediatr.Unit, error) {
+	return mediatr.Unit{}, mediatr.Publish(ctx, &StockReserved{})
+}
+
+func (h StockHandlers) HandleStockReserved(ctx context.Context, event *StockReserved) error {
+	_, err := mediatr.Send[*PlaceOrder, mediatr.Unit](ctx, &PlaceOrder{})
+	return err
+}
+
+type AuditHandler struct{}
+
+func (h *AuditHandler) Handle(ctx context.Context, event *Audit) error {
+	return nil
+}
+
+func Checkout(ctx context.Context) error {
+	if _, err := mediatr.Send[*PlaceOrder, mediatr.Unit](ctx, &PlaceOrder{}); err != nil {
+		return err
+	}
+	_, err := mediatr.Send[*CancelOrder, mediatr.Unit](ctx, &CancelOrder{})
+	return err
+}
@@ -0,0 +1,285 @@
+package main
+
+import (
+	"bytes"
+	"embed"
+	"fmt"
+	"go/token"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/imports"
+)
+
+//go:embed templates/*.tmpl
+var templateFiles embed.FS
+
+var templates = template.Must(template.New("").Funcs(template.FuncMap{
+	"params":      params,
+	"assignments": assignments,
+	"jsonName":    lowerCamelCase,
+	"copies":      copies,
+	"samples":     samples,
+	"fieldNames":  fieldNames,
+	"quotedNames": quotedNames,
+	"words": func(feature string) string {
+		return strings.ReplaceAll(feature, "_", " ")
+	},
+}).ParseFS(templateFiles, "templates/*.tmpl"))
+
+// templateData is the data of the templates, with the names derived from the spec
+type templateData struct {
+	Spec          *Spec
+	ImportPath    string
+	Imports       []string
+	ModulePackage string
+	KindPackage   string
+	Request       string
+	RequestVar    string
+	Response      string
+	Handler       string
+	FileBase      string
+	Event         string
+	EventVar      string
+	EventHandler  string
+	EventFileBase string
+	// EventCopiedFields are the fields of the event set from the request fields with the same name and type
+	EventCopiedFields []Field
+	// EventOtherFields are the fields of the event left to set by the handler
+	EventOtherFields []Field
+}
+
+// generatedFile is a file to generate, relative to the feature directory
+type generatedFile struct {
+	path     string
+	template string
+}
+
+// generate writes the files of the slice in dir/feature, and returns their paths. The import path of dir
+// is found from the go.mod file of its module. Existing files are only overwritten with force.
+func generate(spec *Spec, dir string, force bool) ([]string, error) {
+	if err := spec.validate(); err != nil {
+		return nil, err
+	}
+
+	featureDir, err := filepath.Abs(filepath.Join(dir, spec.Feature))
+	if err != nil {
+		return nil, err
+	}
+	importPath, err := importPathOf(featureDir)
+	if err != nil {
+		return nil, err
+	}
+
+	data := newTemplateData(spec, importPath)
+	files := []generatedFile{
+		{path: filepath.Join(data.KindPackage, data.FileBase+".go"), template: "request.go.tmpl"},
+		{path: filepath.Join(data.KindPackage, data.FileBase+"_handler.go"), template: "handler.go.tmpl"},
+		{path: filepath.Join("dtos", data.FileBase+"_response.go"), template: "response.go.tmpl"},
+		{path: "module.go", template: "module.go.tmpl"},
+		{path: "module_test.go", template: "module_test.go.tmpl"},
+	}
+	if spec.Event != nil {
+		files = append(files,
+			generatedFile{path: filepath.Join("events", data.EventFileBase+".go"), template: "event.go.tmpl"},
+			generatedFile{path: filepath.Join("events", data.EventFileBase+"_handler.go"), template: "event_handler.go.tmpl"},
+		)
+	}
+
+	if !force {
+		for _, file := range files {
+			path := filepath.Join(featureDir, file.path)
+			if _, err := os.Stat(path); err == nil {
+				return nil, fmt.Errorf("%s already exists, use -force to overwrite it", path)
+			}
+		}
+	}
+
+	var written []string
+	for _, file := range files {
+		path := filepath.Join(featureDir, file.path)
+		if err := render(path, file.template, data); err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+func newTemplateData(spec *Spec, importPath string) *templateData {
+	kind := strings.ToUpper(spec.Kind[:1]) + spec.Kind[1:]
+	data := &templateData{
+		Spec:          spec,
+		ImportPath:    importPath,
+		Imports:       spec.importLines(),
+		ModulePackage: strings.ReplaceAll(spec.Feature, "_", ""),
+		KindPackage:   spec.Kind + "s",
+		Request:       spec.Name + kind,
+		RequestVar:    spec.Kind,
+		Response:      spec.Name + kind + "Response",
+		Handler:       spec.Name + kind + "Handler",
+		FileBase:      snakeCase(spec.Name),
+	}
+	if spec.Kind == "query" {
+		data.KindPackage = "queries"
+	}
+	if spec.Event != nil {
+		data.Event = spec.Event.Name + "Event"
+		data.EventVar = lowerCamelCase(data.Event)
+		data.EventHandler = spec.Event.Name + "EventHandler"
+		data.EventFileBase = snakeCase(spec.Event.Name)
+		for _, field := range spec.Event.Fields {
+			if slices.Contains(spec.Fields, field) {
+				data.EventCopiedFields = append(data.EventCopiedFields, field)
+			} else {
+				data.EventOtherFields = append(data.EventOtherFields, field)
+			}
+		}
+	}
+
+	return data
+}
+
+// render executes a template, and writes the formatted source with its imports fixed to path
+func render(path string, name string, data *templateData) error {
+	var buffer bytes.Buffer
+	if err := templates.ExecuteTemplate(&buffer, name, data); err != nil {
+		return err
+	}
+
+	source, err := imports.Process(path, buffer.Bytes(), nil)
+	if err != nil {
+		return fmt.Errorf("generated %s is invalid: %w", path, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, source, 0o644)
+}
+
+// importPathOf returns the import path of a directory, from the module path in the go.mod file of its module
+func importPathOf(dir string) (string, error) {
+	for moduleDir := dir; ; moduleDir = filepath.Dir(moduleDir) {
+		data, err := os.ReadFile(filepath.Join(moduleDir, "go.mod"))
+		if err == nil {
+			modulePath := modulePathOf(data)
+			if modulePath == "" {
+				return "", fmt.Errorf("no module path in %s", filepath.Join(moduleDir, "go.mod"))
+			}
+			relative, err := filepath.Rel(moduleDir, dir)
+			if err != nil {
+				return "", err
+			}
+			return strings.TrimSuffix(modulePath+"/"+filepath.ToSlash(relative), "/."), nil
+		}
+		if filepath.Dir(moduleDir) == moduleDir {
+			return "", fmt.Errorf("no go.mod file found for %s", dir)
+		}
+	}
+}
+
+func modulePathOf(goMod []byte) string {
+	for _, line := range strings.Split(string(goMod), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "module" {
+			return strings.Trim(fields[1], `"`)
+		}
+	}
+
+	return ""
+}
+
+// params returns the constructor parameters of fields, e.g. "name string, price float64"
+func params(fields []Field) string {
+	var params []string
+	for _, field := range fields {
+		params = append(params, paramName(field.Name)+" "+field.Type)
+	}
+
+	return strings.Join(params, ", ")
+}
+
+// assignments returns the assignments of the constructor parameters to the fields, e.g. "Name: name, Price: price"
+func assignments(fields []Field) string {
+	var assignments []string
+	for _, field := range fields {
+		assignments = append(assignments, field.Name+": "+paramName(field.Name))
+	}
+
+	return strings.Join(assignments, ", ")
+}
+
+// copies returns the assignments of fields from the same fields of a variable, e.g. "Name: command.Name"
+func copies(fields []Field, from string) string {
+	var copies []string
+	for _, field := range fields {
+		copies = append(copies, field.Name+": "+from+"."+field.Name)
+	}
+
+	return strings.Join(copies, ", ")
+}
+
+// samples returns the assignments of sample values to the fields of basic types, e.g. `Name: "name", Price: 1`,
+// the other fields are left to their zero value
+func samples(fields []Field) string {
+	var samples []string
+	for _, field := range fields {
+		if value, ok := sampleValue(field); ok {
+			samples = append(samples, field.Name+": "+value)
+		}
+	}
+
+	return strings.Join(samples, ", ")
+}
+
+func sampleValue(field Field) (string, bool) {
+	switch field.Type {
+	case "string":
+		return strconv.Quote(lowerCamelCase(field.Name)), true
+	case "bool":
+		return "true", true
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return "1", true
+	default:
+		return "", false
+	}
+}
+
+// fieldNames returns the names of fields, e.g. "Name and Price"
+func fieldNames(fields []Field) string {
+	var names []string
+	for _, field := range fields {
+		names = append(names, field.Name)
+	}
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return strings.Join(names[:len(names)-1], ", ") + " and " + names[len(names)-1]
+}
+
+// quotedNames returns the quoted names of fields, e.g. `"Name", "Price"`
+func quotedNames(fields []Field) string {
+	var names []string
+	for _, field := range fields {
+		names = append(names, strconv.Quote(field.Name))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+func paramName(fieldName string) string {
+	name := lowerCamelCase(fieldName)
+	if token.IsKeyword(name) {
+		return name + "Value"
+	}
+
+	return name
+}
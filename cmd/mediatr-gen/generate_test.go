@@ -0,0 +1,154 @@
+package main
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newModule creates a module requiring the mediatr module of the repository, and returns its directory
+func newModule(t *testing.T) string {
+	dir := t.TempDir()
+	_, file, _, _ := runtime.Caller(0)
+	repository := filepath.Join(filepath.Dir(file), "..", "..")
+	goMod := "module example.com/shop\n\ngo 1.24\n\n" +
+		"require github.com/mehdihadeli/go-mediatr v0.0.0\n\n" +
+		"replace github.com/mehdihadeli/go-mediatr => " + repository + "\n"
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "go.mod"), []byte(goMod), 0o644))
+
+	return dir
+}
+
+func Test_Generate_Should_Scaffold_Command_Slice_With_Event(t *testing.T) {
+	dir := newModule(t)
+	spec := &Spec{
+		Kind:           "command",
+		Name:           "CreateProduct",
+		Feature:        "creating_product",
+		Fields:         []Field{{Name: "Name", Type: "string"}, {Name: "Type", Type: "string"}},
+		ResponseFields: []Field{{Name: "ProductID", Type: "string"}},
+		Event: &Event{Name: "ProductCreated", Fields: []Field{
+			{Name: "Name", Type: "string"},
+			{Name: "Type", Type: "int"},
+			{Name: "CreatedAt", Type: "time.Time"},
+		}},
+	}
+
+	written, err := generate(spec, filepath.Join(dir, "features"), false)
+
+	require.NoError(t, err)
+	feature := filepath.Join(dir, "features", "creating_product")
+	assert.Equal(t, []string{
+		filepath.Join(feature, "commands", "create_product.go"),
+		filepath.Join(feature, "commands", "create_product_handler.go"),
+		filepath.Join(feature, "dtos", "create_product_response.go"),
+		filepath.Join(feature, "module.go"),
+		filepath.Join(feature, "module_test.go"),
+		filepath.Join(feature, "events", "product_created.go"),
+		filepath.Join(feature, "events", "product_created_handler.go"),
+	}, written)
+
+	request, err := os.ReadFile(filepath.Join(feature, "commands", "create_product.go"))
+	require.NoError(t, err)
+	assert.Equal(t, `package commands
+
+type CreateProductCommand struct {
+	Name string
+	Type string
+}
+
+func NewCreateProductCommand(name string, typeValue string) *CreateProductCommand {
+	return &CreateProductCommand{Name: name, Type: typeValue}
+}
+`, string(request))
+
+	module, err := os.ReadFile(filepath.Join(feature, "module.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(module), "package creatingproduct")
+	assert.Contains(t, string(module), `"example.com/shop/features/creating_product/commands"`)
+	assert.Contains(t, string(module), "mediatr.AddNotificationHandler[*events.ProductCreatedEvent](r, events.NewProductCreatedEventHandler())")
+
+	handler, err := os.ReadFile(filepath.Join(feature, "commands", "create_product_handler.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(handler), `	// TODO: set the Type and CreatedAt of the event
+	productCreatedEvent := &events.ProductCreatedEvent{Name: command.Name}
+`)
+
+	moduleTest, err := os.ReadFile(filepath.Join(feature, "module_test.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(moduleTest), `	command := &commands.CreateProductCommand{Name: "name", Type: "type"}
+
+	mediatrtest.Spec[*commands.CreateProductCommand, *dtos.CreateProductCommandResponse]{
+		Given: []mediatrtest.Given{mediatrtest.Installed(NewModule())},
+		When:  command,
+		ThenPublished: []interface{}{
+			&events.ProductCreatedEvent{Name: command.Name},
+		},
+		Ignore: []string{"Type", "CreatedAt"},
+	}.Run(t)
+`)
+
+	event, err := os.ReadFile(filepath.Join(feature, "events", "product_created.go"))
+	require.NoError(t, err)
+	assert.Contains(t, string(event), `import "time"`)
+}
+
+func Test_Generate_Should_Not_Overwrite_Existing_Files(t *testing.T) {
+	dir := newModule(t)
+	spec := &Spec{Kind: "query", Name: "GetProductById"}
+	_, err := generate(spec, dir, false)
+	require.NoError(t, err)
+
+	_, err = generate(spec, dir, false)
+
+	assert.ErrorContains(t, err, "get_product_by_id.go already exists, use -force to overwrite it")
+	_, err = generate(spec, dir, true)
+	assert.NoError(t, err)
+}
+
+func Test_Generate_Should_Return_Error_For_Invalid_Spec(t *testing.T) {
+	dir := newModule(t)
+
+	_, err := generate(&Spec{Kind: "event", Name: "ProductCreated"}, dir, false)
+	assert.EqualError(t, err, `invalid kind "event", expected command or query`)
+
+	_, err = generate(&Spec{Kind: "query", Name: "getProduct"}, dir, false)
+	assert.EqualError(t, err, `invalid name "getProduct", expected an exported Go identifier like CreateProduct`)
+
+	_, err = generate(&Spec{Kind: "query", Name: "GetProduct", Imports: []string{"=github.com/satori/go.uuid"}}, dir, false)
+	assert.EqualError(t, err, `invalid import "=github.com/satori/go.uuid", expected path or name=path`)
+}
+
+func Test_Generated_Slice_Should_Build_And_Pass_Its_Test(t *testing.T) {
+	if testing.Short() {
+		t.Skip("runs the go command")
+	}
+	dir := newModule(t)
+	for _, file := range []string{"get_product_by_id.yaml", "create_product.yaml"} {
+		spec, err := loadSpec(filepath.Join("testdata", file))
+		require.NoError(t, err)
+		_, err = generate(spec, dir, false)
+		require.NoError(t, err)
+	}
+
+	for _, args := range [][]string{{"mod", "tidy"}, {"vet", "./..."}, {"test", "./..."}} {
+		command := exec.Command("go", args...)
+		command.Dir = dir
+		command.Env = append(os.Environ(), "GOFLAGS=-mod=mod", "GOPROXY=off")
+		output, err := command.CombinedOutput()
+		require.NoError(t, err, "go %v: %s", args, output)
+	}
+}
+
+func Test_Names_Should_Follow_Go_Conventions(t *testing.T) {
+	assert.Equal(t, "get_product_by_id", snakeCase("GetProductById"))
+	assert.Equal(t, "parse_url_path", snakeCase("ParseURLPath"))
+	assert.Equal(t, "productID", lowerCamelCase("ProductID"))
+	assert.Equal(t, "url", lowerCamelCase("URL"))
+	assert.Equal(t, "urlPath", lowerCamelCase("URLPath"))
+}
@@ -0,0 +1,87 @@
+// Command mediatr-gen scaffolds the vertical slice of a feature, following the layout of the cqrs example:
+// a command or a query with its handler, its response dto, an optional notification event with its handler,
+// a registration module and a test of the module.
+//
+// Usage:
+//
+//	mediatr-gen -kind command -name CreateProduct [-feature creating_product] [-fields Name:string,Price:float64]
+//	    [-response-fields ProductID:uuid.UUID] [-event ProductCreated] [-event-fields ProductID:uuid.UUID]
+//	    [-imports uuid=github.com/satori/go.uuid] [-dir .] [-force]
+//	mediatr-gen -spec create_product.yaml [-dir .] [-force]
+//
+// Example spec:
+//
+//	feature: creating_product
+//	kind: command
+//	name: CreateProduct
+//	fields:
+//	  - name: Name
+//	    type: string
+//	response_fields:
+//	  - name: ProductID
+//	    type: uuid.UUID
+//	event:
+//	  name: ProductCreated
+//	  fields:
+//	    - name: ProductID
+//	      type: uuid.UUID
+//	imports:
+//	  - uuid=github.com/satori/go.uuid
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+)
+
+func main() {
+	specPath := flag.String("spec", "", "YAML spec of the slice, instead of the flags")
+	kind := flag.String("kind", "command", "kind of request, command or query")
+	name := flag.String("name", "", "name of the request without its kind, e.g. CreateProduct")
+	feature := flag.String("feature", "", "directory of the slice, defaults to the snake case of the name")
+	fields := flag.String("fields", "", "fields of the request, e.g. Name:string,Price:float64")
+	responseFields := flag.String("response-fields", "", "fields of the response, e.g. ProductID:uuid.UUID")
+	event := flag.String("event", "", "name of the event published by the handler without its suffix, e.g. ProductCreated")
+	eventFields := flag.String("event-fields", "", "fields of the event, e.g. ProductID:uuid.UUID")
+	importSpecs := flag.String("imports", "", "packages of the field types, e.g. uuid=github.com/satori/go.uuid")
+	dir := flag.String("dir", ".", "directory the slice is generated in")
+	force := flag.Bool("force", false, "overwrite existing files")
+	flag.Parse()
+
+	spec, err := specFromFlags(*specPath, *kind, *name, *feature, *fields, *responseFields, *event, *eventFields, *importSpecs)
+	if err == nil {
+		var written []string
+		written, err = generate(spec, *dir, *force)
+		for _, path := range written {
+			fmt.Println(path)
+		}
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "mediatr-gen: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func specFromFlags(specPath, kind, name, feature, fields, responseFields, event, eventFields, importSpecs string) (*Spec, error) {
+	if specPath != "" {
+		return loadSpec(specPath)
+	}
+
+	spec := &Spec{Kind: kind, Name: name, Feature: feature, Imports: parseImports(importSpecs)}
+	var err error
+	if spec.Fields, err = parseFields(fields); err != nil {
+		return nil, err
+	}
+	if spec.ResponseFields, err = parseFields(responseFields); err != nil {
+		return nil, err
+	}
+	if event != "" {
+		spec.Event = &Event{Name: event}
+		if spec.Event.Fields, err = parseFields(eventFields); err != nil {
+			return nil, err
+		}
+	}
+
+	return spec, nil
+}
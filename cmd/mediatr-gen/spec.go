@@ -0,0 +1,175 @@
+package main
+
+import (
+	"fmt"
+	"go/token"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Spec describes the vertical slice of a feature: a command or a query with its response,
+// and optionally a notification event published by its handler.
+type Spec struct {
+	// Feature is the directory of the slice, e.g. "creating_product", defaults to the snake case of the name
+	Feature string `yaml:"feature"`
+	// Kind is command or query
+	Kind string `yaml:"kind"`
+	// Name is the name of the request without its kind, e.g. "CreateProduct" for CreateProductCommand
+	Name           string  `yaml:"name"`
+	Fields         []Field `yaml:"fields"`
+	ResponseFields []Field `yaml:"response_fields"`
+	Event          *Event  `yaml:"event"`
+	// Imports are the packages of the field types, as "path" or "name=path", e.g. "uuid=github.com/satori/go.uuid".
+	// Standard library packages are found without import.
+	Imports []string `yaml:"imports"`
+}
+
+// Event is a notification event published by the handler of the request.
+type Event struct {
+	// Name is the name of the event without its suffix, e.g. "ProductCreated" for ProductCreatedEvent
+	Name   string  `yaml:"name"`
+	Fields []Field `yaml:"fields"`
+}
+
+// Field is a field of a request, response or event, its type can be qualified by a package, e.g. "uuid.UUID".
+type Field struct {
+	Name string `yaml:"name"`
+	Type string `yaml:"type"`
+}
+
+// loadSpec reads a YAML spec file
+func loadSpec(path string) (*Spec, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	spec := &Spec{}
+	if err := yaml.Unmarshal(data, spec); err != nil {
+		return nil, fmt.Errorf("invalid spec %s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// parseFields parses fields of the form "Name:string,Price:float64"
+func parseFields(value string) ([]Field, error) {
+	var fields []Field
+	for _, field := range strings.Split(value, ",") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+		name, fieldType, ok := strings.Cut(field, ":")
+		if !ok {
+			return nil, fmt.Errorf("invalid field %q, expected Name:type", field)
+		}
+		fields = append(fields, Field{Name: strings.TrimSpace(name), Type: strings.TrimSpace(fieldType)})
+	}
+
+	return fields, nil
+}
+
+// parseImports parses imports of the form "uuid=github.com/satori/go.uuid,github.com/shopspring/decimal"
+func parseImports(value string) []string {
+	var imports []string
+	for _, importSpec := range strings.Split(value, ",") {
+		if importSpec = strings.TrimSpace(importSpec); importSpec != "" {
+			imports = append(imports, importSpec)
+		}
+	}
+
+	return imports
+}
+
+// importLines returns the import declarations of the spec imports, e.g. `uuid "github.com/satori/go.uuid"`
+func (s *Spec) importLines() []string {
+	var lines []string
+	for _, importSpec := range s.Imports {
+		name, path, aliased := strings.Cut(importSpec, "=")
+		if aliased {
+			lines = append(lines, name+" "+strconv.Quote(path))
+		} else {
+			lines = append(lines, strconv.Quote(importSpec))
+		}
+	}
+
+	return lines
+}
+
+// validate checks the spec and sets its defaults
+func (s *Spec) validate() error {
+	if s.Kind != "command" && s.Kind != "query" {
+		return fmt.Errorf("invalid kind %q, expected command or query", s.Kind)
+	}
+	if !isExportedIdentifier(s.Name) {
+		return fmt.Errorf("invalid name %q, expected an exported Go identifier like CreateProduct", s.Name)
+	}
+	if s.Feature == "" {
+		s.Feature = snakeCase(s.Name)
+	}
+	if s.Event != nil && !isExportedIdentifier(s.Event.Name) {
+		return fmt.Errorf("invalid event name %q, expected an exported Go identifier like ProductCreated", s.Event.Name)
+	}
+
+	allFields := append(append([]Field{}, s.Fields...), s.ResponseFields...)
+	if s.Event != nil {
+		allFields = append(allFields, s.Event.Fields...)
+	}
+	for _, field := range allFields {
+		if !isExportedIdentifier(field.Name) || field.Type == "" {
+			return fmt.Errorf("invalid field %s %s, expected an exported name and a type", field.Name, field.Type)
+		}
+	}
+
+	for _, importSpec := range s.Imports {
+		name, path, aliased := strings.Cut(importSpec, "=")
+		if (aliased && !token.IsIdentifier(name)) || path == "" {
+			return fmt.Errorf("invalid import %q, expected path or name=path", importSpec)
+		}
+	}
+
+	return nil
+}
+
+func isExportedIdentifier(name string) bool {
+	return token.IsIdentifier(name) && token.IsExported(name)
+}
+
+// snakeCase converts a Go identifier to snake case, e.g. "GetProductById" becomes "get_product_by_id"
+func snakeCase(name string) string {
+	var builder strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			startsWord := i > 0 && (unicode.IsLower(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])))
+			if startsWord {
+				builder.WriteRune('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		builder.WriteRune(r)
+	}
+
+	return builder.String()
+}
+
+// lowerCamelCase converts an exported Go identifier to an unexported one, e.g. "ProductID" becomes "productID"
+func lowerCamelCase(name string) string {
+	runes := []rune(name)
+	for i := range runes {
+		if i > 0 && i+1 < len(runes) && unicode.IsLower(runes[i+1]) {
+			break
+		}
+		if !unicode.IsUpper(runes[i]) {
+			break
+		}
+		runes[i] = unicode.ToLower(runes[i])
+	}
+
+	return string(runes)
+}
@@ -0,0 +1,119 @@
+// Command mediatr-register generates the registrations of the handlers marked with a //mediatr:handler
+// comment, with the generic parameters of RegisterRequestHandler and RegisterNotificationHandler inferred from
+// their Handle method.
+//
+// The generated file declares a mediatrHandlers struct with a field for each marked handler, and a
+// registerMediatrHandlers function registering them, which returns an error for the handlers that are not
+// provided. The unmarked types with a Handle method are reported, and fail the generation with -strict.
+//
+// Usage:
+//
+//	mediatr-register [-o zz_mediatr_registrations.go] [-strict] [packages]
+//
+// Example:
+//
+//	//mediatr:handler
+//	type CreateProductCommandHandler struct{ ... }
+//
+//	// in cmd/main.go
+//	//go:generate go run github.com/mehdihadeli/go-mediatr/cmd/mediatr-register ../internal/...
+//
+//	err := registerMediatrHandlers(mediatrHandlers{
+//	    CreateProductCommandHandler: commands.NewCreateProductCommandHandler(productRepository),
+//	})
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/types"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+func main() {
+	output := flag.String("o", "zz_mediatr_registrations.go", "generated file, its directory is the package of the registrations")
+	strict := flag.Bool("strict", false, "fail if a type with a Handle method is not marked with "+directive)
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: mediatr-register [-o file] [-strict] [packages]\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if err := run(*output, flag.Args(), *strict, os.Stderr); err != nil {
+		fmt.Fprintf(os.Stderr, "mediatr-register: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(output string, patterns []string, strict bool, stderr io.Writer) error {
+	if len(patterns) == 0 {
+		patterns = []string{"./..."}
+	}
+	output, err := filepath.Abs(output)
+	if err != nil {
+		return err
+	}
+
+	selfPkgs, err := packages.Load(&packages.Config{Mode: packages.NeedName, Dir: filepath.Dir(output)}, ".")
+	if err != nil {
+		return err
+	}
+	if len(selfPkgs) != 1 || selfPkgs[0].Name == "" {
+		return fmt.Errorf("no package found in %s", filepath.Dir(output))
+	}
+	self := types.NewPackage(selfPkgs[0].PkgPath, selfPkgs[0].Name)
+
+	// the previously generated file is replaced, so that its stale registrations don't break the type checking,
+	// and the type errors of the package using the registrations are ignored, since it may not be generated yet
+	pkgs, err := packages.Load(&packages.Config{
+		Mode:    packages.NeedName | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo | packages.NeedImports | packages.NeedDeps,
+		Overlay: map[string][]byte{output: []byte("package " + self.Name() + "\n")},
+	}, patterns...)
+	if err != nil {
+		return err
+	}
+	if err := packageErrors(pkgs, self.Path()); err != nil {
+		return err
+	}
+
+	marked, unmarked, err := findHandlers(pkgs)
+	if err != nil {
+		return err
+	}
+	for _, h := range unmarked {
+		fmt.Fprintf(stderr, "%s: %s has a Handle method, but is not marked with %s\n", h.position, h.named.Obj().Name(), directive)
+	}
+	if strict && len(unmarked) > 0 {
+		return fmt.Errorf("%d handlers are not marked with %s", len(unmarked), directive)
+	}
+
+	source, err := generate(self, marked)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(output, source, 0o644)
+}
+
+// packageErrors returns the errors of the packages, except the type errors of the package using the registrations
+func packageErrors(pkgs []*packages.Package, selfPath string) error {
+	var messages []string
+	packages.Visit(pkgs, nil, func(pkg *packages.Package) {
+		for _, err := range pkg.Errors {
+			if pkg.PkgPath == selfPath && err.Kind == packages.TypeError {
+				continue
+			}
+			messages = append(messages, err.Error())
+		}
+	})
+	if len(messages) > 0 {
+		return fmt.Errorf("packages contain errors:\n%s", strings.Join(messages, "\n"))
+	}
+
+	return nil
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Run_Should_Generate_Registrations_Of_Marked_Handlers(t *testing.T) {
+	output := filepath.Join("testdata", "app", "zz_mediatr_registrations.go")
+	golden, err := os.ReadFile(output)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = os.WriteFile(output, golden, 0o644)
+	})
+	var stderr bytes.Buffer
+
+	err = run(output, []string{"./testdata/app/..."}, false, &stderr)
+
+	require.NoError(t, err)
+	generated, err := os.ReadFile(output)
+	require.NoError(t, err)
+	assert.Equal(t, string(golden), string(generated))
+	assert.Contains(t, stderr.String(), "catalog.go:34:6: LegacyHandler has a Handle method, but is not marked with //mediatr:handler")
+}
+
+func Test_Run_Should_Fail_On_Unmarked_Handlers_When_Strict(t *testing.T) {
+	output := filepath.Join(t.TempDir(), "zz_mediatr_registrations.go")
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(output), "go.mod"), []byte("module example.com/app\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(filepath.Dir(output), "main.go"), []byte("package main\n"), 0o644))
+
+	err := run(output, []string{"./testdata/app/catalog"}, true, &bytes.Buffer{})
+
+	assert.EqualError(t, err, "1 handlers are not marked with //mediatr:handler")
+	assert.NoFileExists(t, output)
+}
+
+func Test_Run_Should_Fail_On_Marked_Type_Without_Handle_Method(t *testing.T) {
+	output := filepath.Join("testdata", "invalid", "zz_mediatr_registrations.go")
+
+	err := run(output, []string{"./testdata/invalid"}, false, &bytes.Buffer{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "NotAHandler is marked with //mediatr:handler, but has no Handle(context.Context, TRequest) (TResponse, error) or Handle(context.Context, TNotification) error method")
+	assert.NoFileExists(t, output)
+}
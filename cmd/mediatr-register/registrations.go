@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/types"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+const (
+	mediatrPath = "github.com/mehdihadeli/go-mediatr"
+	directive   = "//mediatr:handler"
+)
+
+// handler is a type marked with the directive, and the registration inferred from its Handle method
+type handler struct {
+	named        *types.Named
+	pointer      bool
+	message      types.Type
+	response     types.Type // nil for notification handlers
+	field        string
+	position     string
+	notification bool
+}
+
+// findHandlers returns the types of the packages marked with the directive, and the unmarked types
+// having a Handle method with a handler signature
+func findHandlers(pkgs []*packages.Package) ([]*handler, []*handler, error) {
+	var marked, unmarked []*handler
+	var problems []string
+
+	for _, pkg := range pkgs {
+		for _, file := range pkg.Syntax {
+			for _, decl := range file.Decls {
+				genDecl, ok := decl.(*ast.GenDecl)
+				if !ok {
+					continue
+				}
+				for _, spec := range genDecl.Specs {
+					typeSpec, ok := spec.(*ast.TypeSpec)
+					if !ok {
+						continue
+					}
+					typeName, ok := pkg.TypesInfo.Defs[typeSpec.Name].(*types.TypeName)
+					if !ok {
+						continue
+					}
+					named, ok := typeName.Type().(*types.Named)
+					if !ok || named.TypeParams().Len() > 0 {
+						continue
+					}
+
+					isMarked := hasDirective(typeSpec.Doc) || (len(genDecl.Specs) == 1 && hasDirective(genDecl.Doc))
+					position := pkg.Fset.Position(typeSpec.Pos()).String()
+					h, ok := newHandler(named, position)
+					switch {
+					case isMarked && !ok:
+						problems = append(problems, fmt.Sprintf(
+							"%s: %s is marked with %s, but has no Handle(context.Context, TRequest) (TResponse, error) or Handle(context.Context, TNotification) error method",
+							position, named.Obj().Name(), directive,
+						))
+					case isMarked:
+						marked = append(marked, h)
+					case ok:
+						unmarked = append(unmarked, h)
+					}
+				}
+			}
+		}
+	}
+
+	if len(problems) > 0 {
+		return nil, nil, fmt.Errorf("%s", strings.Join(problems, "\n"))
+	}
+
+	return marked, unmarked, nil
+}
+
+func hasDirective(doc *ast.CommentGroup) bool {
+	if doc == nil {
+		return false
+	}
+
+	return slices.ContainsFunc(doc.List, func(comment *ast.Comment) bool {
+		return strings.TrimSpace(comment.Text) == directive
+	})
+}
+
+// newHandler infers the registration of a type from its Handle method
+func newHandler(named *types.Named, position string) (*handler, bool) {
+	for method := range named.Methods() {
+		if method.Name() != "Handle" {
+			continue
+		}
+
+		signature := method.Type().(*types.Signature)
+		params, results := signature.Params(), signature.Results()
+		if signature.Variadic() || params.Len() != 2 || !isContext(params.At(0).Type()) {
+			return nil, false
+		}
+
+		_, pointer := signature.Recv().Type().(*types.Pointer)
+		h := &handler{named: named, pointer: pointer, message: params.At(1).Type(), position: position}
+		switch {
+		case results.Len() == 1 && isError(results.At(0).Type()):
+			h.notification = true
+			return h, true
+		case results.Len() == 2 && isError(results.At(1).Type()):
+			h.response = results.At(0).Type()
+			return h, true
+		default:
+			return nil, false
+		}
+	}
+
+	return nil, false
+}
+
+func isContext(t types.Type) bool {
+	named, ok := t.(*types.Named)
+
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "context" && named.Obj().Name() == "Context"
+}
+
+func isError(t types.Type) bool {
+	return types.Identical(t, types.Universe.Lookup("error").Type())
+}
+
+// importSet names the imports of the generated file, renaming the packages with the same name
+type importSet struct {
+	self  *types.Package
+	names map[string]string // by path
+	used  map[string]bool   // by name
+}
+
+func newImportSet(self *types.Package) *importSet {
+	return &importSet{
+		self:  self,
+		names: map[string]string{},
+		used:  map[string]bool{"errors": true, "mediatr": true},
+	}
+}
+
+func (s *importSet) qualifier(pkg *types.Package) string {
+	if s.self != nil && pkg.Path() == s.self.Path() {
+		return ""
+	}
+	if name, ok := s.names[pkg.Path()]; ok {
+		return name
+	}
+
+	name := pkg.Name()
+	for i := 2; s.used[name]; i++ {
+		name = pkg.Name() + strconv.Itoa(i)
+	}
+	s.names[pkg.Path()] = name
+	s.used[name] = true
+
+	return name
+}
+
+// generate returns the source of the registrations file of the package self, for the marked handlers
+func generate(self *types.Package, handlers []*handler) ([]byte, error) {
+	slices.SortFunc(handlers, func(a, b *handler) int {
+		if c := strings.Compare(a.named.Obj().Pkg().Path(), b.named.Obj().Pkg().Path()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.named.Obj().Name(), b.named.Obj().Name())
+	})
+
+	imports := newImportSet(self)
+	typeString := func(t types.Type) string {
+		return types.TypeString(t, imports.qualifier)
+	}
+
+	fieldCounts := map[string]int{}
+	for _, h := range handlers {
+		fieldCounts[h.named.Obj().Name()]++
+	}
+	for _, h := range handlers {
+		h.field = h.named.Obj().Name()
+		if fieldCounts[h.field] > 1 {
+			h.field = exported(h.named.Obj().Pkg().Name()) + h.field
+		}
+	}
+
+	var body bytes.Buffer
+	body.WriteString("// mediatrHandlers are the handlers marked with " + directive + ", registered by registerMediatrHandlers\n")
+	body.WriteString("type mediatrHandlers struct {\n")
+	for _, h := range handlers {
+		handlerType := typeString(h.named)
+		if h.pointer {
+			handlerType = "*" + handlerType
+		}
+		fmt.Fprintf(&body, "\t%s %s\n", h.field, handlerType)
+	}
+	body.WriteString("}\n\n")
+
+	body.WriteString("// registerMediatrHandlers registers the handlers marked with " + directive + ",\n")
+	body.WriteString("// it returns an error if one of them is not provided\n")
+	body.WriteString("func registerMediatrHandlers(handlers mediatrHandlers) error {\n")
+	for _, h := range handlers {
+		if h.pointer {
+			fmt.Fprintf(&body, "\tif handlers.%s == nil {\n", h.field)
+			fmt.Fprintf(&body, "\t\treturn errors.New(%s)\n", strconv.Quote("mediatr handler "+h.field+" is not provided"))
+			body.WriteString("\t}\n")
+		}
+		if h.notification {
+			fmt.Fprintf(&body, "\tif err := mediatr.RegisterNotificationHandler[%s](handlers.%s); err != nil {\n", typeString(h.message), h.field)
+		} else {
+			fmt.Fprintf(&body, "\tif err := mediatr.RegisterRequestHandler[%s, %s](handlers.%s); err != nil {\n", typeString(h.message), typeString(h.response), h.field)
+		}
+		body.WriteString("\t\treturn err\n")
+		body.WriteString("\t}\n")
+	}
+	body.WriteString("\n\treturn nil\n")
+	body.WriteString("}\n")
+
+	var source bytes.Buffer
+	source.WriteString("// Code generated by mediatr-register. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&source, "package %s\n\n", self.Name())
+	source.WriteString("import (\n")
+	if slices.ContainsFunc(handlers, func(h *handler) bool { return h.pointer }) {
+		source.WriteString("\t\"errors\"\n\n")
+	}
+	paths := make([]string, 0, len(imports.names))
+	for path := range imports.names {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		name := imports.names[path]
+		if name == lastElement(path) {
+			fmt.Fprintf(&source, "\t%s\n", strconv.Quote(path))
+		} else {
+			fmt.Fprintf(&source, "\t%s %s\n", name, strconv.Quote(path))
+		}
+	}
+	if len(handlers) > 0 {
+		fmt.Fprintf(&source, "\t%s\n", strconv.Quote(mediatrPath))
+	}
+	source.WriteString(")\n\n")
+	source.Write(body.Bytes())
+
+	return format.Source(source.Bytes())
+}
+
+func lastElement(path string) string {
+	return path[strings.LastIndex(path, "/")+1:]
+}
+
+func exported(name string) string {
+	return strings.ToUpper(name[:1]) + name[1:]
+}
@@ -0,0 +1,38 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/catalog/dtos"
+)
+
+type CreateProduct struct {
+	Name string
+}
+
+type ProductCreated struct {
+	Product *dtos.ProductDto
+}
+
+//mediatr:handler
+type CreateProductHandler struct{}
+
+func (h *CreateProductHandler) Handle(ctx context.Context, command *CreateProduct) (*dtos.ProductDto, error) {
+	return &dtos.ProductDto{ID: "1", Name: command.Name}, nil
+}
+
+// AuditHandler records the created products
+//
+//mediatr:handler
+type AuditHandler struct{}
+
+func (h AuditHandler) Handle(ctx context.Context, event *ProductCreated) error {
+	return nil
+}
+
+// LegacyHandler handles CreateProduct, but is not registered
+type LegacyHandler struct{}
+
+func (h *LegacyHandler) Handle(ctx context.Context, command *CreateProduct) (*dtos.ProductDto, error) {
+	return nil, nil
+}
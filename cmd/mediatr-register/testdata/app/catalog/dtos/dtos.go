@@ -0,0 +1,6 @@
+package dtos
+
+type ProductDto struct {
+	ID   string
+	Name string
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	"github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/catalog"
+	"github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/orders"
+)
+
+//go:generate go run github.com/mehdihadeli/go-mediatr/cmd/mediatr-register ./...
+
+type Ping struct{}
+
+//mediatr:handler
+type PingHandler struct{}
+
+func (h PingHandler) Handle(ctx context.Context, request Ping) (string, error) {
+	return "pong", nil
+}
+
+func main() {
+	err := registerMediatrHandlers(mediatrHandlers{
+		CatalogAuditHandler:  catalog.AuditHandler{},
+		CreateProductHandler: &catalog.CreateProductHandler{},
+		OrdersAuditHandler:   &orders.AuditHandler{},
+		PlaceOrderHandler:    &orders.PlaceOrderHandler{},
+		PingHandler:          PingHandler{},
+	})
+	if err != nil {
+		log.Fatal(err)
+	}
+}
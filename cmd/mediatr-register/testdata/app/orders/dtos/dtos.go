@@ -0,0 +1,6 @@
+package dtos
+
+type OrderDto struct {
+	ID        string
+	ProductID string
+}
@@ -0,0 +1,31 @@
+package orders
+
+import (
+	"context"
+
+	"github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/orders/dtos"
+)
+
+type PlaceOrder struct {
+	ProductID string
+}
+
+type OrderPlaced struct {
+	Order *dtos.OrderDto
+}
+
+type (
+	//mediatr:handler
+	PlaceOrderHandler struct{}
+
+	//mediatr:handler
+	AuditHandler struct{}
+)
+
+func (h *PlaceOrderHandler) Handle(ctx context.Context, command PlaceOrder) (*dtos.OrderDto, error) {
+	return &dtos.OrderDto{ID: "1", ProductID: command.ProductID}, nil
+}
+
+func (h *AuditHandler) Handle(ctx context.Context, event *OrderPlaced) error {
+	return nil
+}
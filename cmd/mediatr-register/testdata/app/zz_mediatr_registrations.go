@@ -0,0 +1,53 @@
+// Code generated by mediatr-register. DO NOT EDIT.
+
+package main
+
+import (
+	"errors"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/catalog"
+	"github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/catalog/dtos"
+	"github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/orders"
+	dtos2 "github.com/mehdihadeli/go-mediatr/cmd/mediatr-register/testdata/app/orders/dtos"
+)
+
+// mediatrHandlers are the handlers marked with //mediatr:handler, registered by registerMediatrHandlers
+type mediatrHandlers struct {
+	PingHandler          PingHandler
+	CatalogAuditHandler  catalog.AuditHandler
+	CreateProductHandler *catalog.CreateProductHandler
+	OrdersAuditHandler   *orders.AuditHandler
+	PlaceOrderHandler    *orders.PlaceOrderHandler
+}
+
+// registerMediatrHandlers registers the handlers marked with //mediatr:handler,
+// it returns an error if one of them is not provided
+func registerMediatrHandlers(handlers mediatrHandlers) error {
+	if err := mediatr.RegisterRequestHandler[Ping, string](handlers.PingHandler); err != nil {
+		return err
+	}
+	if err := mediatr.RegisterNotificationHandler[*catalog.ProductCreated](handlers.CatalogAuditHandler); err != nil {
+		return err
+	}
+	if handlers.CreateProductHandler == nil {
+		return errors.New("mediatr handler CreateProductHandler is not provided")
+	}
+	if err := mediatr.RegisterRequestHandler[*catalog.CreateProduct, *dtos.ProductDto](handlers.CreateProductHandler); err != nil {
+		return err
+	}
+	if handlers.OrdersAuditHandler == nil {
+		return errors.New("mediatr handler OrdersAuditHandler is not provided")
+	}
+	if err := mediatr.RegisterNotificationHandler[*orders.OrderPlaced](handlers.OrdersAuditHandler); err != nil {
+		return err
+	}
+	if handlers.PlaceOrderHandler == nil {
+		return errors.New("mediatr handler PlaceOrderHandler is not provided")
+	}
+	if err := mediatr.RegisterRequestHandler[orders.PlaceOrder, *dtos2.OrderDto](handlers.PlaceOrderHandler); err != nil {
+		return err
+	}
+
+	return nil
+}
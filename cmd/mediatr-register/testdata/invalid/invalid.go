@@ -0,0 +1,10 @@
+package invalid
+
+import "context"
+
+//mediatr:handler
+type NotAHandler struct{}
+
+func (h *NotAHandler) Handle(ctx context.Context) error {
+	return nil
+}
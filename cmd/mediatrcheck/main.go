@@ -0,0 +1,16 @@
+// Command mediatrcheck checks the mediatr Send calls of a program against its request handler registrations.
+//
+// It runs standalone or as a go vet tool:
+//
+//	mediatrcheck ./...
+//	go vet -vettool=$(which mediatrcheck) ./...
+package main
+
+import (
+	"github.com/mehdihadeli/go-mediatr/mediatrcheck"
+	"golang.org/x/tools/go/analysis/singlechecker"
+)
+
+func main() {
+	singlechecker.Main(mediatrcheck.Analyzer)
+}
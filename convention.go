@@ -0,0 +1,194 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+// untypedRequestHandler is a request handler whose request and response types are only known at runtime
+type untypedRequestHandler interface {
+	handleRequest(ctx context.Context, request interface{}) (interface{}, error)
+}
+
+// untypedNotificationHandler is a notification handler whose notification type is only known at runtime
+type untypedNotificationHandler interface {
+	handleNotification(ctx context.Context, notification interface{}) error
+}
+
+// RegisterHandlersFrom registers the methods of obj that follow the handler convention, so a single struct
+// can handle several related requests and notifications:
+//
+// - `Handle(ctx context.Context, request TRequest) (TResponse, error)` or `HandleX(...)` handles TRequest
+// - `Handle(ctx context.Context, notification TNotification) error` or `HandleX(...)` handles TNotification
+//
+// Returns an error, without registering anything, if a method named Handle or HandleX doesn't have one of these
+// signatures, if two methods handle the same request type, or if a request type already has a handler.
+//
+// Example:
+//
+//	type ProductQueries struct{ repository *repository.InMemoryProductRepository }
+//
+//	func (q *ProductQueries) HandleGetById(ctx context.Context, query *GetProductByIdQuery) (*GetProductByIdQueryResponse, error)
+//	func (q *ProductQueries) HandleSearch(ctx context.Context, query *SearchProductsQuery) (*SearchProductsQueryResponse, error)
+//
+//	err := mediatr.RegisterHandlersFrom(&ProductQueries{repository: productRepository})
+func RegisterHandlersFrom(obj interface{}) error {
+	value := reflect.ValueOf(obj)
+	if !value.IsValid() {
+		return errors.New("no handlers object provided")
+	}
+
+	objType := value.Type()
+	requestHandlers := map[reflect.Type]*methodHandler{}
+	var notificationHandlers []*methodHandler
+	var problems []string
+
+	for i := 0; i < objType.NumMethod(); i++ {
+		name := objType.Method(i).Name
+		if !strings.HasPrefix(name, "Handle") {
+			continue
+		}
+
+		handler, ok := newMethodHandler(objType, name, value.Method(i))
+		switch {
+		case !ok:
+			problems = append(problems, errors.Errorf(
+				"method %s has signature %s, expected func(context.Context, TRequest) (TResponse, error) or func(context.Context, TNotification) error",
+				name,
+				value.Method(i).Type(),
+			).Error())
+		case handler.responseType == nil:
+			notificationHandlers = append(notificationHandlers, handler)
+		case requestHandlers[handler.requestType] != nil:
+			problems = append(problems, errors.Errorf(
+				"methods %s and %s both handle request %s",
+				requestHandlers[handler.requestType].name,
+				name,
+				handler.requestType,
+			).Error())
+		default:
+			if _, exists := loadRegistry().requests[handler.requestType]; exists {
+				problems = append(problems, errors.Errorf("handler already exists for type %s", handler.requestType).Error())
+			}
+			requestHandlers[handler.requestType] = handler
+		}
+	}
+
+	if len(problems) > 0 {
+		return errors.Errorf("invalid handlers in %s: %s", objType, strings.Join(problems, "; "))
+	}
+	if len(requestHandlers) == 0 && len(notificationHandlers) == 0 {
+		return errors.Errorf("no handler methods found in %s", objType)
+	}
+
+	return updateRegistry(func(next *registrySnapshot) error {
+		for requestType, handler := range requestHandlers {
+			registration := newInstanceRegistration(handler)
+			registration.responseType = handler.responseType
+			if err := next.addRequestHandler(requestType, registration); err != nil {
+				return err
+			}
+		}
+		for _, handler := range notificationHandlers {
+			if err := next.addNotificationHandler(handler.requestType, newInstanceRegistration(handler)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// methodHandler is a request or notification handler method found by RegisterHandlersFrom
+type methodHandler struct {
+	owner        reflect.Type
+	name         string
+	method       reflect.Value
+	requestType  reflect.Type
+	responseType reflect.Type // nil for notification handlers
+}
+
+func newMethodHandler(owner reflect.Type, name string, method reflect.Value) (*methodHandler, bool) {
+	methodType := method.Type()
+	if methodType.IsVariadic() || methodType.NumIn() != 2 || methodType.In(0) != contextType {
+		return nil, false
+	}
+
+	handler := &methodHandler{owner: owner, name: name, method: method, requestType: methodType.In(1)}
+
+	switch {
+	case methodType.NumOut() == 1 && methodType.Out(0) == errorType:
+		return handler, true
+	case methodType.NumOut() == 2 && methodType.Out(1) == errorType:
+		handler.responseType = methodType.Out(0)
+		return handler, true
+	default:
+		return nil, false
+	}
+}
+
+func (h *methodHandler) handlerName() string {
+	return h.owner.String() + "." + h.name
+}
+
+func (h *methodHandler) call(ctx context.Context, request interface{}) []reflect.Value {
+	requestValue := reflect.ValueOf(request)
+	if !requestValue.IsValid() {
+		requestValue = reflect.Zero(h.requestType)
+	}
+
+	return h.method.Call([]reflect.Value{reflect.ValueOf(&ctx).Elem(), requestValue})
+}
+
+func (h *methodHandler) handleRequest(ctx context.Context, request interface{}) (interface{}, error) {
+	results := h.call(ctx, request)
+	err, _ := results[1].Interface().(error)
+
+	return results[0].Interface(), err
+}
+
+func (h *methodHandler) handleNotification(ctx context.Context, notification interface{}) error {
+	err, _ := h.call(ctx, notification)[0].Interface().(error)
+
+	return err
+}
+
+// untypedRequestHandlerAdapter adapts an untyped request handler to the RequestHandler of a Send call
+type untypedRequestHandlerAdapter[TRequest any, TResponse any] struct {
+	handler untypedRequestHandler
+}
+
+func (a untypedRequestHandlerAdapter[TRequest, TResponse]) Handle(ctx context.Context, request TRequest) (TResponse, error) {
+	response, err := a.handler.handleRequest(ctx, request)
+	if err != nil {
+		return *new(TResponse), err
+	}
+
+	typedResponse, ok := response.(TResponse)
+	if !ok && response != nil {
+		return *new(TResponse), errors.Errorf(
+			"invalid response %T for request %T, expected %s",
+			response,
+			request,
+			reflect.TypeOf((*TResponse)(nil)).Elem(),
+		)
+	}
+
+	return typedResponse, nil
+}
+
+// untypedNotificationHandlerAdapter adapts an untyped notification handler to the NotificationHandler of a Publish call
+type untypedNotificationHandlerAdapter[TNotification any] struct {
+	handler untypedNotificationHandler
+}
+
+func (a untypedNotificationHandlerAdapter[TNotification]) Handle(ctx context.Context, notification TNotification) error {
+	return a.handler.handleNotification(ctx, notification)
+}
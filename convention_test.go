@@ -0,0 +1,116 @@
+package mediatr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RegisterHandlersFrom_Should_Register_All_Handler_Methods(t *testing.T) {
+	defer cleanup()
+	handlers := &conventionHandlers{}
+
+	require.NoError(t, RegisterHandlersFrom(handlers))
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+
+	response2, err := Send[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{Data: "test2"})
+	require.NoError(t, err)
+	assert.Equal(t, "test2", response2.Data)
+
+	notification := &NotificationTest{}
+	require.NoError(t, Publish(context.Background(), notification))
+	assert.True(t, notification.Processed)
+	assert.Equal(t, 2, countRequestHandlers())
+}
+
+func Test_RegisterHandlersFrom_Should_Propagate_Handler_Errors(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterHandlersFrom(&conventionHandlers{err: errors.New("some error")}))
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	assert.ErrorContains(t, err, "handler error: some error")
+
+	err = Publish(context.Background(), &NotificationTest{})
+	assert.ErrorContains(t, err, "notification handler failed: some error")
+}
+
+func Test_RegisterHandlersFrom_Should_Return_Error_For_Mismatched_Response_Type(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterHandlersFrom(&conventionHandlers{}))
+
+	_, err := Send[*RequestTest, *ResponseTest2](context.Background(), &RequestTest{})
+
+	assert.ErrorContains(t, err, "invalid response *mediatr.ResponseTest for request *mediatr.RequestTest, expected *mediatr.ResponseTest2")
+}
+
+func Test_RegisterHandlersFrom_Should_Report_Methods_With_Wrong_Signature(t *testing.T) {
+	defer cleanup()
+
+	err := RegisterHandlersFrom(&invalidConventionHandlers{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "method HandleWithoutContext has signature func(*mediatr.RequestTest) (*mediatr.ResponseTest, error)")
+	assert.Contains(t, err.Error(), "method HandleWithoutError has signature func(context.Context, *mediatr.RequestTest) *mediatr.ResponseTest")
+	assert.Equal(t, 0, countRequestHandlers(), "nothing should be registered if a method is invalid")
+}
+
+func Test_RegisterHandlersFrom_Should_Return_Error_If_Request_Already_Has_Handler(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&RequestTestHandler{}))
+
+	err := RegisterHandlersFrom(&conventionHandlers{})
+
+	assert.ErrorContains(t, err, "handler already exists for type *mediatr.RequestTest")
+	assert.Equal(t, 1, countRequestHandlers())
+}
+
+func Test_RegisterHandlersFrom_Should_Return_Error_If_No_Handler_Methods(t *testing.T) {
+	err := RegisterHandlersFrom(&RequestTest{})
+
+	assert.ErrorContains(t, err, "no handler methods found in *mediatr.RequestTest")
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type conventionHandlers struct {
+	err error
+}
+
+func (c *conventionHandlers) HandleRequestTest(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+
+	return &ResponseTest{Data: request.Data}, nil
+}
+
+func (c *conventionHandlers) Handle(ctx context.Context, request *RequestTest2) (*ResponseTest2, error) {
+	return &ResponseTest2{Data: request.Data}, nil
+}
+
+func (c *conventionHandlers) HandleNotificationTest(ctx context.Context, notification *NotificationTest) error {
+	notification.Processed = true
+
+	return c.err
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type invalidConventionHandlers struct {
+}
+
+func (c *invalidConventionHandlers) HandleValid(ctx context.Context, request *RequestTest2) (*ResponseTest2, error) {
+	return &ResponseTest2{}, nil
+}
+
+func (c *invalidConventionHandlers) HandleWithoutContext(request *RequestTest) (*ResponseTest, error) {
+	return &ResponseTest{}, nil
+}
+
+func (c *invalidConventionHandlers) HandleWithoutError(ctx context.Context, request *RequestTest) *ResponseTest {
+	return &ResponseTest{}
+}
@@ -0,0 +1,179 @@
+package mediatr
+
+import (
+	"encoding/json"
+	"iter"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Description lists the request handlers, notification handlers and pipeline behaviors registered in the
+// mediator at the time Describe was called. Handlers provided by a HandlerResolver are not listed.
+// It marshals to JSON with the requests, notifications and behaviors keys.
+type Description struct {
+	requests      []RequestDescription
+	notifications []NotificationDescription
+	behaviors     []string
+}
+
+// RequestDescription describes the handler of a request type.
+type RequestDescription struct {
+	RequestType  string             `json:"requestType"`
+	ResponseType string             `json:"responseType"`
+	Handler      HandlerDescription `json:"handler"`
+	// Behaviors is the chain of behaviors wrapping the handler, outermost first
+	Behaviors []string `json:"behaviors"`
+}
+
+// NotificationDescription describes the handlers of a notification type, in the order they are invoked.
+type NotificationDescription struct {
+	NotificationType string               `json:"notificationType"`
+	Handlers         []HandlerDescription `json:"handlers"`
+}
+
+// HandlerDescription describes a registered handler.
+type HandlerDescription struct {
+	// Name is the type of a handler instance, the function of a handler func, the method of a convention
+	// handler, or the function of a factory
+	Name string `json:"name"`
+	// Factory is true when handlers are built by a factory
+	Factory bool `json:"factory"`
+	// Lifetime is the lifetime of the handlers built by a factory, empty for handler instances
+	Lifetime string `json:"lifetime,omitempty"`
+}
+
+// namedHandler is implemented by the handlers the package adapts, so they are described by their origin
+type namedHandler interface {
+	handlerName() string
+}
+
+// Describe returns the registrations of the mediator, sorted by request and notification type.
+//
+// Example:
+//
+//	for request := range mediatr.Describe().Requests() {
+//	    fmt.Printf("%s -> %s: %s\n", request.RequestType, request.ResponseType, request.Handler.Name)
+//	}
+func Describe() *Description {
+	snapshot := loadRegistry()
+
+	behaviors := make([]string, 0, len(snapshot.behaviors))
+	for _, behavior := range snapshot.behaviors {
+		behaviors = append(behaviors, BehaviorName(behavior))
+	}
+
+	description := &Description{behaviors: behaviors}
+
+	for requestType, registration := range snapshot.requests {
+		description.requests = append(description.requests, RequestDescription{
+			RequestType:  typeName(requestType),
+			ResponseType: typeName(registration.responseType),
+			Handler:      describeHandler(registration),
+			Behaviors:    slices.Clone(behaviors),
+		})
+	}
+
+	for notificationType, registrations := range snapshot.notifications {
+		handlers := make([]HandlerDescription, 0, len(registrations))
+		for _, registration := range registrations {
+			handlers = append(handlers, describeHandler(registration))
+		}
+		description.notifications = append(description.notifications, NotificationDescription{
+			NotificationType: typeName(notificationType),
+			Handlers:         handlers,
+		})
+	}
+
+	slices.SortFunc(description.requests, func(a, b RequestDescription) int {
+		return strings.Compare(a.RequestType, b.RequestType)
+	})
+	slices.SortFunc(description.notifications, func(a, b NotificationDescription) int {
+		return strings.Compare(a.NotificationType, b.NotificationType)
+	})
+
+	return description
+}
+
+// Requests returns the descriptions of the request handlers.
+func (d *Description) Requests() iter.Seq[RequestDescription] {
+	return slices.Values(d.requests)
+}
+
+// Notifications returns the descriptions of the notification handlers.
+func (d *Description) Notifications() iter.Seq[NotificationDescription] {
+	return slices.Values(d.notifications)
+}
+
+// Behaviors returns the names of the pipeline behaviors, in registration order.
+func (d *Description) Behaviors() iter.Seq[string] {
+	return slices.Values(d.behaviors)
+}
+
+// Request returns the description of the handler of a request type, by its name like "*commands.CreateProductCommand".
+func (d *Description) Request(requestType string) (RequestDescription, bool) {
+	for _, request := range d.requests {
+		if request.RequestType == requestType {
+			return request, true
+		}
+	}
+
+	return RequestDescription{}, false
+}
+
+// Notification returns the description of the handlers of a notification type, by its name.
+func (d *Description) Notification(notificationType string) (NotificationDescription, bool) {
+	for _, notification := range d.notifications {
+		if notification.NotificationType == notificationType {
+			return notification, true
+		}
+	}
+
+	return NotificationDescription{}, false
+}
+
+// MarshalJSON implements json.Marshaler.
+func (d *Description) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Requests      []RequestDescription      `json:"requests"`
+		Notifications []NotificationDescription `json:"notifications"`
+		Behaviors     []string                  `json:"behaviors"`
+	}{
+		Requests:      nonNil(d.requests),
+		Notifications: nonNil(d.notifications),
+		Behaviors:     nonNil(d.behaviors),
+	})
+}
+
+func describeHandler(registration *handlerRegistration) HandlerDescription {
+	if registration.factory != nil {
+		return HandlerDescription{
+			Name:     funcName(registration.source),
+			Factory:  true,
+			Lifetime: registration.lifetime.String(),
+		}
+	}
+
+	if named, ok := registration.instance.(namedHandler); ok {
+		return HandlerDescription{Name: named.handlerName()}
+	}
+
+	return HandlerDescription{Name: typeName(reflect.TypeOf(registration.instance))}
+}
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return ""
+	}
+
+	return t.String()
+}
+
+// nonNil returns an empty slice for nil, so it marshals to an empty JSON array
+func nonNil[T any](values []T) []T {
+	if values == nil {
+		return []T{}
+	}
+
+	return values
+}
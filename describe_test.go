@@ -0,0 +1,84 @@
+package mediatr
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRequestTestHandler() RequestHandler[*RequestTest, *ResponseTest] {
+	return &RequestTestHandler{}
+}
+
+func Test_Describe_Should_Describe_Request_Handlers(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest2, *ResponseTest2](&RequestTestHandler2{}))
+	require.NoError(t, RegisterRequestHandlerFactory(newRequestTestHandler, WithLifetime(Scoped)))
+	require.NoError(t, RegisterRequestPipelineBehaviors(&PipelineBehaviourTest{}, PipelineBehaviorFunc(upperCaseBehavior)))
+
+	requests := slices.Collect(Describe().Requests())
+
+	assert.Equal(t, []RequestDescription{
+		{
+			RequestType:  "*mediatr.RequestTest",
+			ResponseType: "*mediatr.ResponseTest",
+			Handler:      HandlerDescription{Name: "newRequestTestHandler", Factory: true, Lifetime: "scoped"},
+			Behaviors:    []string{"PipelineBehaviourTest", "upperCaseBehavior"},
+		},
+		{
+			RequestType:  "*mediatr.RequestTest2",
+			ResponseType: "*mediatr.ResponseTest2",
+			Handler:      HandlerDescription{Name: "*mediatr.RequestTestHandler2"},
+			Behaviors:    []string{"PipelineBehaviourTest", "upperCaseBehavior"},
+		},
+	}, requests)
+}
+
+func Test_Describe_Should_Describe_Notification_Handlers_In_Order(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&NotificationTestHandler{}))
+	require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+		return nil
+	}))
+
+	notification, ok := Describe().Notification("*mediatr.NotificationTest")
+
+	require.True(t, ok)
+	require.Len(t, notification.Handlers, 2)
+	assert.Equal(t, HandlerDescription{Name: "*mediatr.NotificationTestHandler"}, notification.Handlers[0])
+	assert.Equal(t, "Test_Describe_Should_Describe_Notification_Handlers_In_Order.func1", notification.Handlers[1].Name)
+}
+
+func Test_Describe_Should_Name_Convention_Handlers_By_Method(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterHandlersFrom(&conventionHandlers{}))
+
+	request, ok := Describe().Request("*mediatr.RequestTest")
+
+	require.True(t, ok)
+	assert.Equal(t, "*mediatr.ResponseTest", request.ResponseType)
+	assert.Equal(t, "*mediatr.conventionHandlers.HandleRequestTest", request.Handler.Name)
+}
+
+func Test_Describe_Should_Marshal_To_JSON(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+
+	data, err := json.Marshal(Describe())
+
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"requests": [{
+			"requestType": "*mediatr.RequestTest",
+			"responseType": "*mediatr.ResponseTest",
+			"handler": {"name": "*mediatr.echoRequestHandler", "factory": false},
+			"behaviors": []
+		}],
+		"notifications": [],
+		"behaviors": []
+	}`, string(data))
+}
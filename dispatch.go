@@ -0,0 +1,176 @@
+package mediatr
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// The dispatch slots cache, per request and notification type, the registration and the typed handler of the
+// instance registrations, and the compiled behavior chain of the requests, so that Send and Publish resolve the
+// handler of a type registered in the registry without reflection, map lookup nor type assertion. A slot is keyed
+// by typeKey, and is valid for the registry snapshot it was built from: the slots of the previous snapshots are
+// rebuilt on their next dispatch.
+var (
+	requestSlots      atomic.Pointer[map[interface{}]interface{}] // map[typeKey]*requestSlot[TRequest, TResponse]
+	notificationSlots atomic.Pointer[map[interface{}]interface{}] // map[typeKey]*notificationSlot[TNotification]
+	slotsMutex        sync.Mutex
+
+	// dispatchSlotsEnabled is disabled by the benchmarks of the reflection path
+	dispatchSlotsEnabled = true
+)
+
+// requestSlot is the cached registration of a request type, with the behavior chain of its Send calls.
+// handler is nil for factory registrations
+type requestSlot[TRequest any, TResponse any] struct {
+	snapshot     *registrySnapshot
+	registration *handlerRegistration
+	handler      RequestHandler[TRequest, TResponse]
+	pipeline     *pipeline[TRequest, TResponse]
+}
+
+// notificationSlot is the cached registrations of a notification type, with the notification behaviors.
+// handlers[i] is nil for factory registrations
+type notificationSlot[TNotification any] struct {
+	snapshot      *registrySnapshot
+	registrations []*handlerRegistration
+	handlers      []NotificationHandler[TNotification]
+	behaviors     []NotificationPipelineBehavior
+}
+
+// typeKey identifies T without reflection: the nil pointers of distinct types are distinct interface values,
+// and converting them to an interface doesn't allocate
+func typeKey[T any]() interface{} {
+	return (*T)(nil)
+}
+
+// isInterface reports whether T is an interface type, whose registrations are keyed by the dynamic type of
+// the message and can't be cached by T
+func isInterface[T any]() bool {
+	return interface{}(*new(T)) == nil
+}
+
+// loadRequestSlot returns the registration of TRequest and, for an instance registration, its typed handler,
+// with the compiled behavior chain, all read from the current registry snapshot. The registration is looked up by
+// the dynamic type of the request, and cached, on the first dispatch of the snapshot. The slot of an uncached
+// registration has no handler.
+func loadRequestSlot[TRequest any, TResponse any](ctx context.Context, request TRequest) (*requestSlot[TRequest, TResponse], error) {
+	snapshot := loadRegistry()
+	if !dispatchSlotsEnabled || isInterface[TRequest]() {
+		return uncachedRequestSlot[TRequest, TResponse](ctx, snapshot, request)
+	}
+
+	if slots := requestSlots.Load(); slots != nil {
+		if slot, ok := (*slots)[typeKey[TRequest]()].(*requestSlot[TRequest, TResponse]); ok && slot.snapshot == snapshot {
+			return slot, nil
+		}
+	}
+
+	registration, ok := snapshot.requests[reflect.TypeOf(request)]
+	if !ok {
+		// the registrations of the resolver are not cached, the resolver may return a different handler per call
+		return uncachedRequestSlot[TRequest, TResponse](ctx, snapshot, request)
+	}
+
+	slot := &requestSlot[TRequest, TResponse]{
+		snapshot:     snapshot,
+		registration: registration,
+		pipeline:     compilePipeline[TRequest, TResponse](snapshot.behaviors),
+	}
+	if registration.factory == nil {
+		// an invalid handler isn't cached, buildRequestHandler reports it on every dispatch
+		slot.handler, _ = buildRequestHandler[TRequest, TResponse](ctx, registration)
+	}
+	storeSlot(&requestSlots, typeKey[TRequest](), slot)
+
+	return slot, nil
+}
+
+func uncachedRequestSlot[TRequest any, TResponse any](
+	ctx context.Context,
+	snapshot *registrySnapshot,
+	request TRequest,
+) (*requestSlot[TRequest, TResponse], error) {
+	registration, err := snapshot.requestRegistration(ctx, reflect.TypeOf(request))
+
+	return &requestSlot[TRequest, TResponse]{
+		snapshot:     snapshot,
+		registration: registration,
+		pipeline:     compilePipeline[TRequest, TResponse](snapshot.behaviors),
+	}, err
+}
+
+// loadNotificationSlot returns the registrations of TNotification and the typed handlers of the instance registrations,
+// with the notification behaviors, all read from the current registry snapshot. The registrations are looked up by
+// the dynamic type of the notification, and cached, on the first dispatch of the snapshot. The slot of uncached
+// registrations has no handlers.
+func loadNotificationSlot[TNotification any](ctx context.Context, notification TNotification) (*notificationSlot[TNotification], error) {
+	snapshot := loadRegistry()
+	if !dispatchSlotsEnabled || isInterface[TNotification]() {
+		return uncachedNotificationSlot[TNotification](ctx, snapshot, notification)
+	}
+
+	if slots := notificationSlots.Load(); slots != nil {
+		if slot, ok := (*slots)[typeKey[TNotification]()].(*notificationSlot[TNotification]); ok && slot.snapshot == snapshot {
+			return slot, nil
+		}
+	}
+
+	registrations, ok := snapshot.notifications[reflect.TypeOf(notification)]
+	if !ok {
+		return uncachedNotificationSlot[TNotification](ctx, snapshot, notification)
+	}
+
+	slot := &notificationSlot[TNotification]{
+		snapshot:      snapshot,
+		registrations: registrations,
+		handlers:      make([]NotificationHandler[TNotification], len(registrations)),
+		behaviors:     snapshot.notificationBehaviors,
+	}
+	for i, registration := range registrations {
+		if registration.factory == nil {
+			slot.handlers[i], _ = buildNotificationHandler[TNotification](ctx, registration)
+		}
+	}
+	storeSlot(&notificationSlots, typeKey[TNotification](), slot)
+
+	return slot, nil
+}
+
+func uncachedNotificationSlot[TNotification any](
+	ctx context.Context,
+	snapshot *registrySnapshot,
+	notification TNotification,
+) (*notificationSlot[TNotification], error) {
+	registrations, err := snapshot.notificationRegistrations(ctx, reflect.TypeOf(notification))
+
+	return &notificationSlot[TNotification]{
+		snapshot:      snapshot,
+		registrations: registrations,
+		behaviors:     snapshot.notificationBehaviors,
+	}, err
+}
+
+// handler returns the handler of the registration at index, built for the call if it isn't cached
+func (s *notificationSlot[TNotification]) handler(ctx context.Context, index int) (NotificationHandler[TNotification], error) {
+	if s.handlers != nil && s.handlers[index] != nil {
+		return s.handlers[index], nil
+	}
+
+	return buildNotificationHandler[TNotification](ctx, s.registrations[index])
+}
+
+// storeSlot adds a slot to a copy of the slots, so that dispatches read the slots without locking
+func storeSlot(slots *atomic.Pointer[map[interface{}]interface{}], key interface{}, slot interface{}) {
+	slotsMutex.Lock()
+	defer slotsMutex.Unlock()
+
+	updated := map[interface{}]interface{}{}
+	if current := slots.Load(); current != nil {
+		updated = maps.Clone(*current)
+	}
+	updated[key] = slot
+	slots.Store(&updated)
+}
@@ -0,0 +1,95 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_Should_Use_Handler_Registered_After_Clear(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	require.NoError(t, err)
+
+	ClearRequestRegistrations()
+	_, err = Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	assert.ErrorContains(t, err, "no handler for request *mediatr.RequestTest")
+
+	require.NoError(t, RegisterRequestHandlerFunc(func(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+		return &ResponseTest{Data: "replaced"}, nil
+	}))
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, "replaced", response.Data)
+}
+
+func Test_Publish_Should_Dispatch_To_Handler_Registered_After_First_Publish(t *testing.T) {
+	defer cleanup()
+	first, second := &dispatchCountingHandler{}, &dispatchCountingHandler{}
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](first))
+	require.NoError(t, Publish(context.Background(), &NotificationTest{}))
+
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](second))
+	require.NoError(t, Publish(context.Background(), &NotificationTest{}))
+
+	assert.Equal(t, 2, first.count)
+	assert.Equal(t, 1, second.count)
+}
+
+func Test_Send_Should_Not_Cache_Resolver_Handlers(t *testing.T) {
+	defer cleanup()
+	resolver := &mapHandlerResolver{requestHandlers: map[reflect.Type]interface{}{
+		reflect.TypeOf(&RequestTest{}): &echoRequestHandler{},
+	}}
+	SetHandlerResolver(resolver)
+	defer SetHandlerResolver(nil)
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	require.NoError(t, err)
+
+	resolver.requestHandlers = map[reflect.Type]interface{}{}
+	_, err = Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+
+	assert.ErrorContains(t, err, "no handler for request *mediatr.RequestTest")
+}
+
+func Test_Send_Should_Return_Error_For_Cached_Handler_With_Other_Response_Type(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	require.NoError(t, err)
+
+	_, err = Send[*RequestTest, *ResponseTest2](context.Background(), &RequestTest{})
+
+	assert.ErrorContains(t, err, "invalid handler for request *mediatr.RequestTest")
+}
+
+func Test_LoadRequestSlot_Should_Not_Allocate_Once_Cached(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	ctx, request := context.Background(), &RequestTest{}
+	_, err := loadRequestSlot[*RequestTest, *ResponseTest](ctx, request)
+	require.NoError(t, err)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		slot, _ := loadRequestSlot[*RequestTest, *ResponseTest](ctx, request)
+		if slot.handler == nil {
+			t.Fatal("handler should be cached")
+		}
+	})
+
+	assert.Zero(t, allocs)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type dispatchCountingHandler struct {
+	count int
+}
+
+func (c *dispatchCountingHandler) Handle(ctx context.Context, notification *NotificationTest) error {
+	c.count++
+	return nil
+}
@@ -0,0 +1,63 @@
+package mediatr
+
+import (
+	"context"
+	"sync"
+)
+
+// Executor runs the concurrent work of the mediator: the requests dispatched with SendAsync, and the notification
+// handlers of the Publish calls made WithParallelHandlers. The default executor runs each task in its own goroutine,
+// a test can set a deterministic executor, running the tasks one at a time in a reproducible order.
+type Executor interface {
+	// Go runs task concurrently with the caller.
+	Go(task func())
+
+	// Wait blocks until done is closed, or returns ctx.Err() when ctx is done first. An executor running the tasks
+	// itself runs them while waiting, the waited work being one of them.
+	Wait(ctx context.Context, done <-chan struct{}) error
+}
+
+var (
+	executor      Executor = goroutineExecutor{}
+	executorMutex sync.RWMutex
+)
+
+// SetExecutor sets the executor of the concurrent dispatches. Pass nil to restore the default executor,
+// running each task in its own goroutine.
+//
+// Example:
+//
+//	mediatr.SetExecutor(myPoolExecutor)
+func SetExecutor(e Executor) {
+	executorMutex.Lock()
+	defer executorMutex.Unlock()
+
+	if e == nil {
+		e = goroutineExecutor{}
+	}
+	executor = e
+}
+
+func currentExecutor() Executor {
+	executorMutex.RLock()
+	defer executorMutex.RUnlock()
+
+	return executor
+}
+
+// goroutineExecutor is the default executor, running each task in its own goroutine
+type goroutineExecutor struct {
+}
+
+func (goroutineExecutor) Go(task func()) {
+	go task()
+}
+
+func (goroutineExecutor) Wait(ctx context.Context, done <-chan struct{}) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,109 @@
+package mediatr
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SendAsync_Should_Run_On_Executor(t *testing.T) {
+	defer cleanup()
+	defer SetExecutor(nil)
+	executor := &countingExecutor{}
+	SetExecutor(executor)
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+
+	response, err := SendAsync[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"}).Await(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+	assert.Equal(t, int64(1), executor.tasks.Load())
+	assert.Equal(t, int64(1), executor.waits.Load())
+}
+
+func Test_WhenAll_Should_Not_Run_Tasks_On_Executor(t *testing.T) {
+	defer cleanup()
+	defer SetExecutor(nil)
+	executor := &countingExecutor{}
+	SetExecutor(executor)
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+
+	_, err := WhenAll(
+		SendAsync[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "first"}),
+		SendAsync[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "second"}),
+	).Await(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), executor.tasks.Load(), "only the requests should run on the executor")
+}
+
+func Test_Publish_With_Parallel_Handlers_Should_Run_Handlers_Concurrently(t *testing.T) {
+	defer cleanup()
+	// each handler waits for the other to start, which deadlocks if they run one after the other
+	first, second := make(chan struct{}), make(chan struct{})
+	require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+		close(first)
+		<-second
+		return nil
+	}))
+	require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+		close(second)
+		<-first
+		return nil
+	}))
+
+	err := Publish(context.Background(), &NotificationTest{}, WithParallelHandlers())
+
+	assert.NoError(t, err)
+}
+
+func Test_Publish_With_Parallel_Handlers_Should_Return_First_Error_In_Registration_Order(t *testing.T) {
+	defer cleanup()
+	var handled atomic.Int64
+	for _, err := range []error{nil, errors.New("first"), errors.New("second")} {
+		require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+			handled.Add(1)
+			return err
+		}))
+	}
+
+	err := Publish(context.Background(), &NotificationTest{}, WithParallelHandlers())
+
+	assert.EqualError(t, err, "notification handler failed: first")
+	assert.Equal(t, int64(3), handled.Load(), "all the handlers should run")
+}
+
+func Test_Publish_With_Parallel_Handlers_Should_Return_Panic_As_PanicError(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+		panic("boom")
+	}))
+
+	err := Publish(context.Background(), &NotificationTest{}, WithParallelHandlers())
+
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr), "expected PanicError, got %v", err)
+	assert.Equal(t, "boom", panicErr.Value)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+// countingExecutor runs the tasks in their own goroutine, counting the tasks and the waits
+type countingExecutor struct {
+	goroutineExecutor
+	tasks atomic.Int64
+	waits atomic.Int64
+}
+
+func (e *countingExecutor) Go(task func()) {
+	e.tasks.Add(1)
+	e.goroutineExecutor.Go(task)
+}
+
+func (e *countingExecutor) Wait(ctx context.Context, done <-chan struct{}) error {
+	e.waits.Add(1)
+	return e.goroutineExecutor.Wait(ctx, done)
+}
@@ -0,0 +1,95 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// PipelineBehaviorFunc is a function used as a PipelineBehavior.
+// It is named after the function, e.g. "LogRequest" for a function `behaviours.LogRequest`.
+//
+// Example:
+//
+//	err := mediatr.RegisterRequestPipelineBehaviors(mediatr.PipelineBehaviorFunc(
+//	    func(ctx context.Context, request interface{}, next mediatr.RequestHandlerFunc) (interface{}, error) {
+//	        log.Printf("handling %T", request)
+//	        return next(ctx)
+//	    }))
+type PipelineBehaviorFunc func(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error)
+
+// Handle calls f(ctx, request, next).
+func (f PipelineBehaviorFunc) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	return f(ctx, request, next)
+}
+
+// Name returns the name of the function, without its package.
+func (f PipelineBehaviorFunc) Name() string {
+	return funcName(f)
+}
+
+// RegisterRequestHandlerFunc registers a function as the handler of a specific request type.
+// Returns an error if a handler is already registered for the request type.
+//
+// Example:
+//
+//	err := mediatr.RegisterRequestHandlerFunc(func(ctx context.Context, query *GetProductByIdQuery) (*GetProductByIdQueryResponse, error) {
+//	    // handle query
+//	})
+func RegisterRequestHandlerFunc[TRequest any, TResponse any](fn func(ctx context.Context, request TRequest) (TResponse, error)) error {
+	return RegisterRequestHandler[TRequest, TResponse](requestHandlerFunc[TRequest, TResponse](fn))
+}
+
+// RegisterNotificationHandlerFunc registers a function as a handler for notifications of specific type.
+// Multiple handlers can be registered for the same notification type.
+func RegisterNotificationHandlerFunc[TEvent any](fn func(ctx context.Context, notification TEvent) error) error {
+	return RegisterNotificationHandler[TEvent](notificationHandlerFunc[TEvent](fn))
+}
+
+// requestHandlerFunc is a function registered as a RequestHandler
+type requestHandlerFunc[TRequest any, TResponse any] func(ctx context.Context, request TRequest) (TResponse, error)
+
+func (f requestHandlerFunc[TRequest, TResponse]) Handle(ctx context.Context, request TRequest) (TResponse, error) {
+	return f(ctx, request)
+}
+
+func (f requestHandlerFunc[TRequest, TResponse]) handlerName() string {
+	return funcName(f)
+}
+
+// notificationHandlerFunc is a function registered as a NotificationHandler
+type notificationHandlerFunc[TNotification any] func(ctx context.Context, notification TNotification) error
+
+func (f notificationHandlerFunc[TNotification]) Handle(ctx context.Context, notification TNotification) error {
+	return f(ctx, notification)
+}
+
+func (f notificationHandlerFunc[TNotification]) handlerName() string {
+	return funcName(f)
+}
+
+// funcName returns the name of a function without its package path, e.g. "behaviours.LogRequest" becomes "LogRequest"
+func funcName(fn interface{}) string {
+	function := runtime.FuncForPC(reflect.ValueOf(fn).Pointer())
+	if function == nil {
+		return reflect.TypeOf(fn).String()
+	}
+
+	name := function.Name()
+	name = name[strings.LastIndex(name, "/")+1:]
+
+	return name[strings.Index(name, ".")+1:]
+}
+
+// sameBehavior reports whether two behaviors are the same registration: behaviors of the same type,
+// or the same function for PipelineBehaviorFunc
+func sameBehavior(behavior PipelineBehavior, other PipelineBehavior) bool {
+	behaviorFunc, isFunc := behavior.(PipelineBehaviorFunc)
+	otherFunc, otherIsFunc := other.(PipelineBehaviorFunc)
+	if isFunc && otherIsFunc {
+		return reflect.ValueOf(behaviorFunc).Pointer() == reflect.ValueOf(otherFunc).Pointer()
+	}
+
+	return reflect.TypeOf(behavior) == reflect.TypeOf(other)
+}
@@ -0,0 +1,86 @@
+package mediatr
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RegisterRequestHandlerFunc_Should_Dispatch_Request_To_Function(t *testing.T) {
+	defer cleanup()
+	err := RegisterRequestHandlerFunc(func(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+		return &ResponseTest{Data: request.Data}, nil
+	})
+	require.NoError(t, err)
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+}
+
+func Test_RegisterRequestHandlerFunc_Should_Return_Error_If_Handler_Already_Registered(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&RequestTestHandler{}))
+
+	err := RegisterRequestHandlerFunc(func(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+		return nil, nil
+	})
+
+	assert.ErrorContains(t, err, "handler already exists for type *mediatr.RequestTest")
+}
+
+func Test_RegisterNotificationHandlerFunc_Should_Dispatch_Notification_To_Functions(t *testing.T) {
+	defer cleanup()
+	calls := 0
+	handler := func(ctx context.Context, notification *NotificationTest) error {
+		calls++
+		return nil
+	}
+	require.NoError(t, RegisterNotificationHandlerFunc(handler))
+	require.NoError(t, RegisterNotificationHandlerFunc(handler))
+
+	require.NoError(t, Publish(context.Background(), &NotificationTest{}))
+
+	assert.Equal(t, 2, calls)
+}
+
+func Test_PipelineBehaviorFunc_Should_Wrap_Handler(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestPipelineBehaviors(PipelineBehaviorFunc(upperCaseBehavior), &PipelineBehaviourTest{}))
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "TEST", response.Data)
+}
+
+func Test_PipelineBehaviorFunc_Should_Detect_Duplicate_Functions(t *testing.T) {
+	defer cleanup()
+	err := RegisterRequestPipelineBehaviors(PipelineBehaviorFunc(upperCaseBehavior), PipelineBehaviorFunc(passThroughBehavior))
+	require.NoError(t, err, "different functions should be registered")
+
+	err = RegisterRequestPipelineBehaviors(PipelineBehaviorFunc(upperCaseBehavior))
+	assert.ErrorContains(t, err, "behavior already registered")
+}
+
+func Test_PipelineBehaviorFunc_Should_Be_Named_After_Function(t *testing.T) {
+	assert.Equal(t, "upperCaseBehavior", BehaviorName(PipelineBehaviorFunc(upperCaseBehavior)))
+}
+
+func upperCaseBehavior(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	response, err := next(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResponseTest{Data: strings.ToUpper(response.(*ResponseTest).Data)}, nil
+}
+
+func passThroughBehavior(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	return next(ctx)
+}
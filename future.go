@@ -0,0 +1,200 @@
+package mediatr
+
+import (
+	"context"
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// Future represents the pending response of a request dispatched with SendAsync.
+// A future completes exactly once, either with the handler response or with an error.
+type Future[T any] struct {
+	done     chan struct{}
+	once     sync.Once
+	cancel   context.CancelFunc
+	response T
+	err      error
+
+	// callbacks are called once the future completes, completed is set under the mutex with them
+	mutex     sync.Mutex
+	completed bool
+	callbacks []func()
+}
+
+// PanicError is returned to the awaiter when a handler or pipeline behavior panics
+// while processing an asynchronous request.
+type PanicError struct {
+	Value interface{}
+	Stack []byte
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// Unwrap returns the panic value when it is an error.
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// SendAsync dispatches a request in the background and returns a Future for its response.
+// Errors returned by the pipeline and panics raised by behaviors or the handler are
+// propagated to the awaiter. Cancelling the future cancels the context seen by the pipeline.
+// The request always begins its own Scope, even when ctx has one, and runs on the Executor.
+//
+// Example:
+//
+//	future := mediatr.SendAsync[*MyRequest, *MyResponse](ctx, &MyRequest{})
+//	// do other work
+//	response, err := future.Await(ctx)
+func SendAsync[TRequest any, TResponse any](ctx context.Context, request TRequest, opts ...Option) *Future[TResponse] {
+	// The request may outlive the scope of the caller, so it begins its own scope
+	ctx, cancel := context.WithCancel(detachScope(ctx))
+	future := newFuture[TResponse](cancel)
+
+	currentExecutor().Go(func() {
+		defer cancel()
+		defer func() {
+			if r := recover(); r != nil {
+				future.complete(*new(TResponse), &PanicError{Value: r, Stack: debug.Stack()})
+			}
+		}()
+
+		response, err := Send[TRequest, TResponse](ctx, request, opts...)
+		future.complete(response, err)
+	})
+
+	return future
+}
+
+// Await blocks until the future completes or ctx is done, whichever happens first.
+// Returning because of ctx does not cancel the future.
+func (f *Future[T]) Await(ctx context.Context) (T, error) {
+	if err := currentExecutor().Wait(ctx, f.done); err != nil {
+		return *new(T), err
+	}
+
+	return f.response, f.err
+}
+
+// Done returns a channel that is closed when the future completes.
+func (f *Future[T]) Done() <-chan struct{} {
+	return f.done
+}
+
+// Cancel cancels the context of the underlying request and completes the future
+// with context.Canceled if it has not completed yet.
+func (f *Future[T]) Cancel() {
+	f.cancel()
+	f.complete(*new(T), context.Canceled)
+}
+
+// WhenAll returns a future that completes with the responses of all futures, in the order given.
+// It fails fast: the first error completes the returned future and cancels the remaining futures.
+// Cancelling the returned future cancels all of them.
+func WhenAll[T any](futures ...*Future[T]) *Future[[]T] {
+	result := newFuture[[]T](func() { cancelFutures(futures) })
+	if len(futures) == 0 {
+		result.complete([]T{}, nil)
+		return result
+	}
+
+	responses := make([]T, len(futures))
+	remaining := atomic.Int64{}
+	remaining.Store(int64(len(futures)))
+
+	for i, future := range futures {
+		future.onComplete(func() {
+			if result.isCompleted() {
+				return
+			}
+			if future.err != nil {
+				result.complete(nil, future.err)
+				cancelFutures(futures)
+				return
+			}
+
+			responses[i] = future.response
+			if remaining.Add(-1) == 0 {
+				result.complete(responses, nil)
+			}
+		})
+	}
+
+	return result
+}
+
+// WhenAny returns a future that completes with the response or error of the first future to complete.
+// The other futures keep running; cancelling the returned future cancels all of them.
+func WhenAny[T any](futures ...*Future[T]) *Future[T] {
+	result := newFuture[T](func() { cancelFutures(futures) })
+	if len(futures) == 0 {
+		result.complete(*new(T), errors.New("no futures provided"))
+		return result
+	}
+
+	for _, future := range futures {
+		future.onComplete(func() {
+			result.complete(future.response, future.err)
+		})
+	}
+
+	return result
+}
+
+func newFuture[T any](cancel context.CancelFunc) *Future[T] {
+	return &Future[T]{done: make(chan struct{}), cancel: cancel}
+}
+
+func (f *Future[T]) complete(response T, err error) {
+	var callbacks []func()
+	f.once.Do(func() {
+		f.response = response
+		f.err = err
+		close(f.done)
+
+		f.mutex.Lock()
+		f.completed = true
+		callbacks, f.callbacks = f.callbacks, nil
+		f.mutex.Unlock()
+	})
+
+	// the callbacks may complete this future again, e.g. by cancelling it, so they are called outside of once
+	for _, callback := range callbacks {
+		callback()
+	}
+}
+
+func (f *Future[T]) isCompleted() bool {
+	select {
+	case <-f.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// onComplete calls callback once the future completes, on the goroutine completing it,
+// or right away if it is already completed
+func (f *Future[T]) onComplete(callback func()) {
+	f.mutex.Lock()
+	if !f.completed {
+		f.callbacks = append(f.callbacks, callback)
+		f.mutex.Unlock()
+		return
+	}
+	f.mutex.Unlock()
+
+	callback()
+}
+
+func cancelFutures[T any](futures []*Future[T]) {
+	for _, future := range futures {
+		future.Cancel()
+	}
+}
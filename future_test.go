@@ -0,0 +1,178 @@
+package mediatr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_SendAsync_Should_Complete_With_Handler_Response(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&RequestTestHandler{}))
+
+	future := SendAsync[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	response, err := future.Await(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+
+	select {
+	case <-future.Done():
+	default:
+		t.Error("done channel should be closed after completion")
+	}
+}
+
+func Test_SendAsync_Should_Propagate_Handler_Error(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest2, *ResponseTest2](&RequestTestHandler3{}))
+
+	_, err := SendAsync[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{}).Await(context.Background())
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "some error")
+}
+
+func Test_SendAsync_Should_Propagate_Panic_As_PanicError(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&panickingRequestHandler{}))
+
+	_, err := SendAsync[*RequestTest, *ResponseTest](context.Background(), &RequestTest{}).Await(context.Background())
+
+	var panicErr *PanicError
+	require.True(t, errors.As(err, &panicErr), "expected PanicError, got %v", err)
+	assert.Equal(t, "boom", panicErr.Value)
+	assert.NotEmpty(t, panicErr.Stack)
+}
+
+func Test_Future_Cancel_Should_Cancel_Handler_Context(t *testing.T) {
+	defer cleanup()
+	handler := &blockingRequestHandler{started: make(chan struct{}), stopped: make(chan error, 1)}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+
+	future := SendAsync[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	<-handler.started
+	future.Cancel()
+
+	_, err := future.Await(context.Background())
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, <-handler.stopped, context.Canceled)
+}
+
+func Test_Future_Await_Should_Return_When_Context_Done(t *testing.T) {
+	defer cleanup()
+	handler := &blockingRequestHandler{started: make(chan struct{}), stopped: make(chan error, 1)}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+
+	future := SendAsync[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	defer future.Cancel()
+	<-handler.started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err := future.Await(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func Test_WhenAll_Should_Collect_Responses_In_Order(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+
+	ctx := context.Background()
+	responses, err := WhenAll(
+		SendAsync[*RequestTest, *ResponseTest](ctx, &RequestTest{Data: "first"}),
+		SendAsync[*RequestTest, *ResponseTest](ctx, &RequestTest{Data: "second"}),
+	).Await(ctx)
+
+	require.NoError(t, err)
+	require.Len(t, responses, 2)
+	assert.Equal(t, "first", responses[0].Data)
+	assert.Equal(t, "second", responses[1].Data)
+}
+
+func Test_WhenAll_Should_Fail_Fast_And_Cancel_Remaining_Futures(t *testing.T) {
+	defer cleanup()
+	handler := &blockingRequestHandler{started: make(chan struct{}), stopped: make(chan error, 1)}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+	require.NoError(t, RegisterRequestHandler[*RequestTest2, *ResponseTest2](&RequestTestHandler3{}))
+
+	ctx := context.Background()
+	blocking := SendAsync[*RequestTest, *ResponseTest](ctx, &RequestTest{})
+	failing := SendAsync[*RequestTest2, *ResponseTest2](ctx, &RequestTest2{})
+	<-handler.started
+
+	_, err := WhenAll(blocking, toResponseTestFuture(failing)).Await(ctx)
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "some error")
+	assert.ErrorIs(t, <-handler.stopped, context.Canceled)
+}
+
+func Test_WhenAny_Should_Complete_With_First_Completed_Future(t *testing.T) {
+	defer cleanup()
+	handler := &blockingRequestHandler{started: make(chan struct{}), stopped: make(chan error, 1)}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+
+	ctx := context.Background()
+	blocking := SendAsync[*RequestTest, *ResponseTest](ctx, &RequestTest{})
+	defer blocking.Cancel()
+	<-handler.started
+
+	completed := newFuture[*ResponseTest](func() {})
+	completed.complete(&ResponseTest{Data: "fast"}, nil)
+
+	response, err := WhenAny(blocking, completed).Await(ctx)
+
+	require.NoError(t, err)
+	assert.Equal(t, "fast", response.Data)
+}
+
+func Test_WhenAny_Should_Return_Error_If_No_Futures(t *testing.T) {
+	_, err := WhenAny[*ResponseTest]().Await(context.Background())
+	assert.EqualError(t, err, "no futures provided")
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type panickingRequestHandler struct {
+}
+
+func (c *panickingRequestHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	panic("boom")
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type echoRequestHandler struct {
+}
+
+func (c *echoRequestHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	return &ResponseTest{Data: request.Data}, nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type blockingRequestHandler struct {
+	started chan struct{}
+	stopped chan error
+}
+
+func (c *blockingRequestHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	close(c.started)
+	<-ctx.Done()
+	c.stopped <- ctx.Err()
+
+	return nil, ctx.Err()
+}
+
+func toResponseTestFuture(future *Future[*ResponseTest2]) *Future[*ResponseTest] {
+	result := newFuture[*ResponseTest](future.Cancel)
+	go func() {
+		_, err := future.Await(context.Background())
+		result.complete(nil, err)
+	}()
+
+	return result
+}
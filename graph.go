@@ -0,0 +1,114 @@
+package mediatr
+
+import (
+	"fmt"
+	"strings"
+)
+
+// DOT renders the registrations as a Graphviz DOT digraph: each request flows through its behavior chain
+// into its handler, and each notification fans out to its handlers in invocation order.
+//
+// Example:
+//
+//	os.WriteFile("mediatr.dot", []byte(mediatr.Describe().DOT()), 0o644)
+func (d *Description) DOT() string {
+	var builder strings.Builder
+	builder.WriteString("digraph mediatr {\n")
+	builder.WriteString("\trankdir=LR;\n")
+	builder.WriteString("\tnode [shape=box];\n")
+
+	for i, request := range d.requests {
+		requestID := fmt.Sprintf("r%d", i)
+		fmt.Fprintf(&builder, "\t%s [label=%s, shape=ellipse];\n", requestID, dotQuote(request.RequestType))
+
+		previous := requestID
+		for j, behavior := range request.Behaviors {
+			behaviorID := fmt.Sprintf("r%db%d", i, j)
+			fmt.Fprintf(&builder, "\t%s [label=%s, shape=cds];\n", behaviorID, dotQuote(behavior))
+			fmt.Fprintf(&builder, "\t%s -> %s;\n", previous, behaviorID)
+			previous = behaviorID
+		}
+
+		handlerID := fmt.Sprintf("r%dh", i)
+		label := handlerLabel(request.Handler) + "\n" + "returns " + request.ResponseType
+		fmt.Fprintf(&builder, "\t%s [label=%s];\n", handlerID, dotQuote(label))
+		fmt.Fprintf(&builder, "\t%s -> %s;\n", previous, handlerID)
+	}
+
+	for i, notification := range d.notifications {
+		notificationID := fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&builder, "\t%s [label=%s, shape=hexagon];\n", notificationID, dotQuote(notification.NotificationType))
+
+		for j, handler := range notification.Handlers {
+			handlerID := fmt.Sprintf("n%dh%d", i, j)
+			fmt.Fprintf(&builder, "\t%s [label=%s];\n", handlerID, dotQuote(handlerLabel(handler)))
+			fmt.Fprintf(&builder, "\t%s -> %s [label=\"%d\"];\n", notificationID, handlerID, j+1)
+		}
+	}
+
+	builder.WriteString("}\n")
+
+	return builder.String()
+}
+
+// Mermaid renders the registrations as a Mermaid flowchart, with the same graph as DOT.
+//
+// Example:
+//
+//	fmt.Println("```mermaid\n" + mediatr.Describe().Mermaid() + "```")
+func (d *Description) Mermaid() string {
+	var builder strings.Builder
+	builder.WriteString("flowchart LR\n")
+
+	for i, request := range d.requests {
+		requestID := fmt.Sprintf("r%d", i)
+		fmt.Fprintf(&builder, "\t%s([%s])\n", requestID, mermaidQuote(request.RequestType))
+
+		previous := requestID
+		for j, behavior := range request.Behaviors {
+			behaviorID := fmt.Sprintf("r%db%d", i, j)
+			fmt.Fprintf(&builder, "\t%s[[%s]]\n", behaviorID, mermaidQuote(behavior))
+			fmt.Fprintf(&builder, "\t%s --> %s\n", previous, behaviorID)
+			previous = behaviorID
+		}
+
+		handlerID := fmt.Sprintf("r%dh", i)
+		label := handlerLabel(request.Handler) + "<br/>" + "returns " + request.ResponseType
+		fmt.Fprintf(&builder, "\t%s[%s]\n", handlerID, mermaidQuote(label))
+		fmt.Fprintf(&builder, "\t%s --> %s\n", previous, handlerID)
+	}
+
+	for i, notification := range d.notifications {
+		notificationID := fmt.Sprintf("n%d", i)
+		fmt.Fprintf(&builder, "\t%s{{%s}}\n", notificationID, mermaidQuote(notification.NotificationType))
+
+		for j, handler := range notification.Handlers {
+			handlerID := fmt.Sprintf("n%dh%d", i, j)
+			fmt.Fprintf(&builder, "\t%s[%s]\n", handlerID, mermaidQuote(handlerLabel(handler)))
+			fmt.Fprintf(&builder, "\t%s -->|%d| %s\n", notificationID, j+1, handlerID)
+		}
+	}
+
+	return builder.String()
+}
+
+// handlerLabel is the name of a handler, with the lifetime of the handlers built by a factory
+func handlerLabel(handler HandlerDescription) string {
+	if handler.Factory {
+		return fmt.Sprintf("%s (%s factory)", handler.Name, handler.Lifetime)
+	}
+
+	return handler.Name
+}
+
+func dotQuote(label string) string {
+	label = strings.ReplaceAll(label, `\`, `\\`)
+	label = strings.ReplaceAll(label, `"`, `\"`)
+	label = strings.ReplaceAll(label, "\n", `\n`)
+
+	return `"` + label + `"`
+}
+
+func mermaidQuote(label string) string {
+	return `"` + strings.ReplaceAll(label, `"`, "#quot;") + `"`
+}
@@ -0,0 +1,63 @@
+package mediatr
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func registerGraphTestHandlers(t *testing.T) {
+	require.NoError(t, RegisterRequestHandlerFactory(newRequestTestHandler, WithLifetime(Singleton)))
+	require.NoError(t, RegisterRequestPipelineBehaviors(&PipelineBehaviourTest{}))
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&NotificationTestHandler{}))
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&NotificationTestHandler{}))
+}
+
+func Test_Description_DOT_Should_Render_Behavior_Chains_And_Notification_Handlers(t *testing.T) {
+	defer cleanup()
+	registerGraphTestHandlers(t)
+
+	dot := Describe().DOT()
+
+	assert.Equal(t, `digraph mediatr {
+	rankdir=LR;
+	node [shape=box];
+	r0 [label="*mediatr.RequestTest", shape=ellipse];
+	r0b0 [label="PipelineBehaviourTest", shape=cds];
+	r0 -> r0b0;
+	r0h [label="newRequestTestHandler (singleton factory)\nreturns *mediatr.ResponseTest"];
+	r0b0 -> r0h;
+	n0 [label="*mediatr.NotificationTest", shape=hexagon];
+	n0h0 [label="*mediatr.NotificationTestHandler"];
+	n0 -> n0h0 [label="1"];
+	n0h1 [label="*mediatr.NotificationTestHandler"];
+	n0 -> n0h1 [label="2"];
+}
+`, dot)
+}
+
+func Test_Description_Mermaid_Should_Render_Behavior_Chains_And_Notification_Handlers(t *testing.T) {
+	defer cleanup()
+	registerGraphTestHandlers(t)
+
+	mermaid := Describe().Mermaid()
+
+	assert.Equal(t, `flowchart LR
+	r0(["*mediatr.RequestTest"])
+	r0b0[["PipelineBehaviourTest"]]
+	r0 --> r0b0
+	r0h["newRequestTestHandler (singleton factory)<br/>returns *mediatr.ResponseTest"]
+	r0b0 --> r0h
+	n0{{"*mediatr.NotificationTest"}}
+	n0h0["*mediatr.NotificationTestHandler"]
+	n0 -->|1| n0h0
+	n0h1["*mediatr.NotificationTestHandler"]
+	n0 -->|2| n0h1
+`, mermaid)
+}
+
+func Test_Quote_Should_Escape_Labels(t *testing.T) {
+	assert.Equal(t, `"say \"hi\"\nC:\\"`, dotQuote("say \"hi\"\nC:\\"))
+	assert.Equal(t, `"say #quot;hi#quot;"`, mermaidQuote(`say "hi"`))
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"cqrsexample/internal/products/features/creating_product/commands"
+	"cqrsexample/internal/products/features/creating_product/dtos"
+	"cqrsexample/internal/products/features/creating_product/events"
+	dtos2 "cqrsexample/internal/products/features/getting_product_by_id/dtos"
+	"cqrsexample/internal/products/features/getting_product_by_id/queries"
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/mehdihadeli/go-mediatr/mediatrjournal"
+)
+
+// recordTraffic appends the traffic of the mediator to the journal at path, until the returned file is closed
+func recordTraffic(path string) (*os.File, *mediatrjournal.Recorder, error) {
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	recorder := mediatrjournal.NewRecorder(file)
+	if err := mediatr.RegisterRequestPipelineBehaviors(recorder.RequestBehavior()); err != nil {
+		return nil, nil, err
+	}
+	if err := mediatr.RegisterNotificationPipelineBehaviors(recorder.NotificationBehavior()); err != nil {
+		return nil, nil, err
+	}
+
+	return file, recorder, nil
+}
+
+// replayTraffic replays the journal at path against the installed handlers, and returns an error listing the
+// responses that differ from the recorded ones
+func replayTraffic(ctx context.Context, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	types := mediatrjournal.NewTypes()
+	if err := mediatrjournal.RegisterRequest[*commands.CreateProductCommand, *dtos.CreateProductCommandResponse](types); err != nil {
+		return err
+	}
+	if err := mediatrjournal.RegisterRequest[*queries.GetProductByIdQuery, *dtos2.GetProductByIdQueryResponse](types); err != nil {
+		return err
+	}
+	if err := mediatrjournal.RegisterNotification[*events.ProductCreatedEvent](types); err != nil {
+		return err
+	}
+
+	replayer := &mediatrjournal.Replayer{Types: types}
+	report, err := replayer.Replay(ctx, file)
+	if err != nil {
+		return err
+	}
+	if !report.OK() {
+		return fmt.Errorf("%s", report)
+	}
+	fmt.Println(report)
+
+	return nil
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"log"
 	"os"
 	"os/signal"
@@ -9,9 +10,11 @@ import (
 
 	"cqrsexample/docs"
 	"cqrsexample/internal/products/api"
+	creatingproduct "cqrsexample/internal/products/features/creating_product"
 	"cqrsexample/internal/products/features/creating_product/commands"
 	"cqrsexample/internal/products/features/creating_product/dtos"
 	"cqrsexample/internal/products/features/creating_product/events"
+	gettingproductbyid "cqrsexample/internal/products/features/getting_product_by_id"
 	dtos2 "cqrsexample/internal/products/features/getting_product_by_id/dtos"
 	"cqrsexample/internal/products/features/getting_product_by_id/queries"
 	"cqrsexample/internal/products/repository"
@@ -25,6 +28,10 @@ import (
 //swag init --parseDependency --parseInternal --parseDepth 1 -g ./cmd/main.go
 
 func main() {
+	journal := flag.String("journal", "", "append the requests and notifications to a JSON Lines journal")
+	replay := flag.String("replay", "", "replay a journal against the handlers and report the response differences, instead of serving")
+	flag.Parse()
+
 	ctx, cancel := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM, syscall.SIGINT)
 	defer cancel()
 
@@ -32,35 +39,47 @@ func main() {
 	productRepository := repository.NewInMemoryProductRepository()
 
 	//////////////////////////////////////////////////////////////////////////////////////////////
-	// Register request handlers to the mediatr
-
-	createProductCommandHandler := commands.NewCreateProductCommandHandler(productRepository)
-	getByIdQueryHandler := queries.NewGetProductByIdHandler(productRepository)
-
-	err := mediatr.RegisterRequestHandler[*commands.CreateProductCommand, *dtos.CreateProductCommandResponse](createProductCommandHandler)
-	if err != nil {
-		log.Fatal(err)
+	// Record the traffic before the other behaviors, so the requests are recorded as sent
+	if *journal != "" {
+		file, recorder, err := recordTraffic(*journal)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer func() {
+			if err := recorder.Err(); err != nil {
+				log.Print("(Journal) err", err)
+			}
+			file.Close()
+		}()
 	}
 
-	err = mediatr.RegisterRequestHandler[*queries.GetProductByIdQuery, *dtos2.GetProductByIdQueryResponse](getByIdQueryHandler)
+	//////////////////////////////////////////////////////////////////////////////////////////////
+	// Install the features modules, registering their handlers and pipelines to the mediatr
+	err := mediatr.Install(
+		behaviours.NewModule(),
+		creatingproduct.NewModule(productRepository),
+		gettingproductbyid.NewModule(productRepository),
+	)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	//////////////////////////////////////////////////////////////////////////////////////////////
-	// Register notification handlers to the mediatr
-	notificationHandler := events.NewProductCreatedEventHandler()
-	err = mediatr.RegisterNotificationHandler[*events.ProductCreatedEvent](notificationHandler)
+	// Fail at startup, instead of on the first request, if a handler is missing
+	err = mediatr.Validate(
+		mediatr.RequireRequest[*commands.CreateProductCommand, *dtos.CreateProductCommandResponse](),
+		mediatr.RequireRequest[*queries.GetProductByIdQuery, *dtos2.GetProductByIdQueryResponse](),
+		mediatr.RequireNotification[*events.ProductCreatedEvent](),
+		mediatr.RejectUnhandledNotifications(),
+	)
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	//////////////////////////////////////////////////////////////////////////////////////////////
-	// Register request handlers pipeline to the mediatr
-	loggerPipeline := &behaviours.RequestLoggerBehaviour{}
-	err = mediatr.RegisterRequestPipelineBehaviors(loggerPipeline)
-	if err != nil {
-		log.Fatal(err)
+	if *replay != "" {
+		if err := replayTraffic(ctx, *replay); err != nil {
+			log.Fatal(err)
+		}
+		return
 	}
 
 	//////////////////////////////////////////////////////////////////////////////////////////////
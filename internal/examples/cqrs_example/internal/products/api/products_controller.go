@@ -44,7 +44,11 @@ func (pc *ProductsController) createProduct() echo.HandlerFunc {
 		}
 
 		command := commands.NewCreateProductCommand(request.Name, request.Description, request.Price)
-		result, err := mediatr.Send[*commands.CreateProductCommand, *dtos3.CreateProductCommandResponse](ctx.Request().Context(), command)
+		result, err := mediatr.Send[*commands.CreateProductCommand, *dtos3.CreateProductCommandResponse](
+			ctx.Request().Context(),
+			command,
+			mediatr.WithMetadata("logger_pipeline", true),
+		)
 
 		if err != nil {
 			return err
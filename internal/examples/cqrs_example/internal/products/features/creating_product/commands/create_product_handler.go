@@ -20,8 +20,8 @@ func NewCreateProductCommandHandler(productRepository *repository.InMemoryProduc
 }
 
 func (c *CreateProductCommandHandler) Handle(ctx context.Context, command *CreateProductCommand) (*dtos.CreateProductCommandResponse, error) {
-	isLoggerPipelineEnabled := ctx.Value("logger_pipeline").(bool)
-	if isLoggerPipelineEnabled {
+	// set by the caller with `mediatr.WithMetadata("logger_pipeline", true)`
+	if isLoggerPipelineEnabled, _ := mediatr.MetadataValue[bool](ctx, "logger_pipeline"); isLoggerPipelineEnabled {
 		fmt.Println("[CreateProductCommandHandler]: logging pipeline is enabled")
 	}
 
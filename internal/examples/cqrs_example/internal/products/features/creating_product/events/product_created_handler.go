@@ -2,6 +2,9 @@ package events
 
 import (
 	"context"
+	"log"
+
+	"github.com/mehdihadeli/go-mediatr"
 )
 
 type ProductCreatedEventHandler struct {
@@ -12,6 +15,10 @@ func NewProductCreatedEventHandler() *ProductCreatedEventHandler {
 }
 
 func (c *ProductCreatedEventHandler) Handle(ctx context.Context, event *ProductCreatedEvent) error {
+	// The event is caused by the `CreateProductCommand` message that published it, and shares its correlation id
+	metadata, _ := mediatr.MetadataFromContext(ctx)
+	log.Printf("[ProductCreatedEventHandler]: message %s, correlation %s, caused by %s", metadata.MessageID, metadata.CorrelationID, metadata.CausationID)
+
 	//Do something with the event here !
 
 	return nil
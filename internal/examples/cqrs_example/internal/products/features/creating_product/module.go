@@ -0,0 +1,36 @@
+package creatingproduct
+
+import (
+	"cqrsexample/internal/products/features/creating_product/commands"
+	"cqrsexample/internal/products/features/creating_product/dtos"
+	"cqrsexample/internal/products/features/creating_product/events"
+	"cqrsexample/internal/products/repository"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// Module registers the handlers of the creating product feature
+type Module struct {
+	productRepository *repository.InMemoryProductRepository
+}
+
+func NewModule(productRepository *repository.InMemoryProductRepository) *Module {
+	return &Module{productRepository: productRepository}
+}
+
+func (m *Module) Name() string {
+	return "creating_product"
+}
+
+func (m *Module) DependsOn() []string {
+	return []string{"behaviours"}
+}
+
+func (m *Module) Register(r mediatr.Registrar) error {
+	createProductCommandHandler := commands.NewCreateProductCommandHandler(m.productRepository)
+	err := mediatr.AddRequestHandler[*commands.CreateProductCommand, *dtos.CreateProductCommandResponse](r, createProductCommandHandler)
+	if err != nil {
+		return err
+	}
+
+	return mediatr.AddNotificationHandler[*events.ProductCreatedEvent](r, events.NewProductCreatedEventHandler())
+}
@@ -0,0 +1,31 @@
+package gettingproductbyid
+
+import (
+	"cqrsexample/internal/products/features/getting_product_by_id/dtos"
+	"cqrsexample/internal/products/features/getting_product_by_id/queries"
+	"cqrsexample/internal/products/repository"
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// Module registers the handlers of the getting product by id feature
+type Module struct {
+	productRepository *repository.InMemoryProductRepository
+}
+
+func NewModule(productRepository *repository.InMemoryProductRepository) *Module {
+	return &Module{productRepository: productRepository}
+}
+
+func (m *Module) Name() string {
+	return "getting_product_by_id"
+}
+
+func (m *Module) DependsOn() []string {
+	return []string{"behaviours"}
+}
+
+func (m *Module) Register(r mediatr.Registrar) error {
+	getByIdQueryHandler := queries.NewGetProductByIdHandler(m.productRepository)
+
+	return mediatr.AddRequestHandler[*queries.GetProductByIdQuery, *dtos.GetProductByIdQueryResponse](r, getByIdQueryHandler)
+}
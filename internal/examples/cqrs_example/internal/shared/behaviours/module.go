@@ -0,0 +1,25 @@
+package behaviours
+
+import (
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// Module registers the pipeline behaviours shared by all the features
+type Module struct {
+}
+
+func NewModule() *Module {
+	return &Module{}
+}
+
+func (m *Module) Name() string {
+	return "behaviours"
+}
+
+func (m *Module) DependsOn() []string {
+	return nil
+}
+
+func (m *Module) Register(r mediatr.Registrar) error {
+	return mediatr.AddPipelineBehaviors(r, &RequestLoggerBehaviour{})
+}
@@ -10,12 +10,14 @@ import (
 type RequestLoggerBehaviour struct {
 }
 
+// Name is used for skipping the behaviour per call with `mediatr.SkipBehavior("logger")`
+func (r *RequestLoggerBehaviour) Name() string {
+	return "logger"
+}
+
 func (r *RequestLoggerBehaviour) Handle(ctx context.Context, request interface{}, next mediatr.RequestHandlerFunc) (interface{}, error) {
 	log.Printf("logging some stuff before handling the request")
 
-	// https://golang.org/pkg/context/#Context
-	ctx = context.WithValue(ctx, "logger_pipeline", true)
-
 	response, err := next(ctx)
 	if err != nil {
 		return nil, err
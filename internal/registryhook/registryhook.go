@@ -0,0 +1,23 @@
+// Package registryhook lets the test support packages of the module isolate the global registrations of the
+// mediator, and observe its calls, without exporting the registry from the mediatr package.
+package registryhook
+
+import "context"
+
+// Isolate replaces the registrations, the installed modules and the handler resolver of the mediator with empty
+// ones, and returns a function restoring them. It is set by the mediatr package.
+var Isolate func() (restore func())
+
+// Observe sets the observer of the Send and Publish calls of the mediator, and returns a function restoring the
+// previous one. It is set by the mediatr package.
+var Observe func(observer Observer) (restore func())
+
+// Observer is notified of the Send and Publish calls dispatched to the pipeline behaviors. Unlike a behavior, it
+// isn't a registration: it isn't described, cleared or skipped.
+type Observer interface {
+	// Sent is called before the behaviors of a Send call, the returned function is called with its result.
+	Sent(ctx context.Context, request interface{}) (complete func(response interface{}, err error))
+
+	// Published is called before the behaviors of a Publish call, the returned function is called with its result.
+	Published(ctx context.Context, notification interface{}) (complete func(err error))
+}
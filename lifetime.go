@@ -0,0 +1,160 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+// ErrHandlerConstruction is matched, with errors.Is, by the errors returned from Send and Publish
+// when a context factory fails to build a handler. The factory error is available with errors.Unwrap.
+var ErrHandlerConstruction = errors.New("handler construction failed")
+
+// Lifetime controls how often a registered handler factory is invoked.
+type Lifetime int
+
+const (
+	// Transient invokes the factory for every dispatch. It is the default lifetime. The handlers are not disposed
+	// by the mediator, so a factory may return a shared handler implementing io.Closer.
+	Transient Lifetime = iota
+	// Singleton invokes the factory once, on the first dispatch, and reuses the handler afterwards.
+	Singleton
+	// Scoped invokes the factory once per Scope, nested Send and Publish calls of the scope share the handler.
+	Scoped
+)
+
+// String returns the name of the lifetime.
+func (l Lifetime) String() string {
+	switch l {
+	case Transient:
+		return "transient"
+	case Singleton:
+		return "singleton"
+	case Scoped:
+		return "scoped"
+	default:
+		return "unknown"
+	}
+}
+
+// RegistrationOption configures a handler factory registration.
+type RegistrationOption func(*registrationOptions)
+
+type registrationOptions struct {
+	lifetime Lifetime
+}
+
+// WithLifetime sets the lifetime of the handlers built by a factory.
+//
+// Example:
+//
+//	err := mediatr.RegisterRequestHandlerFactory(factory, mediatr.WithLifetime(mediatr.Scoped))
+func WithLifetime(lifetime Lifetime) RegistrationOption {
+	return func(o *registrationOptions) {
+		o.lifetime = lifetime
+	}
+}
+
+// handlerRegistration is a registered handler instance, or a factory with the lifetime of the handlers it builds
+type handlerRegistration struct {
+	instance interface{}
+	factory  func(ctx context.Context) (interface{}, error)
+	lifetime Lifetime
+
+	// source is the factory as registered and responseType the response type of a request handler, for Describe
+	source       interface{}
+	responseType reflect.Type
+
+	singletonMutex sync.Mutex
+	singletonBuilt atomic.Bool
+	singleton      interface{}
+}
+
+// handlerConstructionError wraps the error of a factory, and matches ErrHandlerConstruction
+type handlerConstructionError struct {
+	err error
+}
+
+func (e *handlerConstructionError) Error() string {
+	return ErrHandlerConstruction.Error() + ": " + e.err.Error()
+}
+
+func (e *handlerConstructionError) Unwrap() error {
+	return e.err
+}
+
+func (e *handlerConstructionError) Is(target error) bool {
+	return target == ErrHandlerConstruction
+}
+
+func newInstanceRegistration(handler interface{}) *handlerRegistration {
+	return &handlerRegistration{instance: handler}
+}
+
+func newFactoryRegistration(
+	source interface{},
+	factory func(ctx context.Context) (interface{}, error),
+	opts []RegistrationOption,
+) *handlerRegistration {
+	options := registrationOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return &handlerRegistration{factory: factory, lifetime: options.lifetime, source: source}
+}
+
+// resolve returns the handler to use for a dispatch made with ctx, building it according to the lifetime
+func (r *handlerRegistration) resolve(ctx context.Context) (interface{}, error) {
+	if r.factory == nil {
+		return r.instance, nil
+	}
+
+	switch r.lifetime {
+	case Singleton:
+		return r.resolveSingleton(ctx)
+	case Scoped:
+		scope, ok := ScopeFromContext(ctx)
+		if !ok {
+			return nil, errors.New("scoped handler resolved outside of a scope")
+		}
+		return scope.resolve(ctx, r)
+	default:
+		return r.build(ctx)
+	}
+}
+
+// resolveSingleton builds the singleton handler on the first call, a failed construction is retried on the next call
+func (r *handlerRegistration) resolveSingleton(ctx context.Context) (interface{}, error) {
+	if r.singletonBuilt.Load() {
+		return r.singleton, nil
+	}
+
+	r.singletonMutex.Lock()
+	defer r.singletonMutex.Unlock()
+
+	if r.singletonBuilt.Load() {
+		return r.singleton, nil
+	}
+
+	handler, err := r.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	r.singleton = handler
+	r.singletonBuilt.Store(true)
+
+	return handler, nil
+}
+
+func (r *handlerRegistration) build(ctx context.Context) (interface{}, error) {
+	handler, err := r.factory(ctx)
+	if err != nil {
+		return nil, &handlerConstructionError{err: err}
+	}
+
+	return handler, nil
+}
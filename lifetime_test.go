@@ -0,0 +1,275 @@
+package mediatr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_RequestHandlerFactory_Transient_Should_Build_Handler_Per_Send(t *testing.T) {
+	defer cleanup()
+	factory := &disposableHandlerFactory{}
+	require.NoError(t, RegisterRequestHandlerFactory(factory.requestFactory()))
+
+	sendTimes(t, 3)
+
+	assert.Equal(t, 3, factory.built)
+	assert.Equal(t, 0, factory.closed, "transient handlers should not be disposed with a scope")
+}
+
+func Test_RequestHandlerFactory_Transient_Should_Not_Close_Shared_Handler(t *testing.T) {
+	defer cleanup()
+	factory := &disposableHandlerFactory{}
+	shared := &disposableHandler{factory: factory}
+	require.NoError(t, RegisterRequestHandlerFactory(func() RequestHandler[*RequestTest, *ResponseTest] {
+		return shared
+	}))
+
+	ctx, scope := NewScope(context.Background())
+	_, err := Send[*RequestTest, *ResponseTest](ctx, &RequestTest{})
+	require.NoError(t, err)
+	require.NoError(t, scope.Close())
+	sendTimes(t, 2)
+
+	assert.Equal(t, 0, factory.closed, "a long-lived handler returned by a transient factory should stay open")
+}
+
+func Test_RequestHandlerFactory_Singleton_Should_Build_Handler_Once(t *testing.T) {
+	defer cleanup()
+	factory := &disposableHandlerFactory{}
+	require.NoError(t, RegisterRequestHandlerFactory(factory.requestFactory(), WithLifetime(Singleton)))
+
+	sendTimes(t, 3)
+
+	assert.Equal(t, 1, factory.built)
+	assert.Equal(t, 0, factory.closed, "singleton handlers should not be disposed with a scope")
+}
+
+func Test_RequestHandlerFactory_Scoped_Should_Share_Handler_With_Nested_Calls(t *testing.T) {
+	defer cleanup()
+	factory := &disposableHandlerFactory{}
+	require.NoError(t, RegisterRequestHandlerFactory(factory.requestFactory(), WithLifetime(Scoped)))
+	require.NoError(t, RegisterRequestHandler[*RequestTest2, *ResponseTest2](&nestedSendingHandler{times: 3}))
+
+	_, err := Send[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, factory.built, "nested calls should share the scoped handler of the top-level call")
+	assert.Equal(t, 1, factory.closed, "scoped handler should be disposed when the top-level call returns")
+
+	_, err = Send[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{})
+	require.NoError(t, err)
+	assert.Equal(t, 2, factory.built, "each top-level call should have its own scope")
+}
+
+func Test_Scoped_ContextFactory_Should_Send_Nested_Request_To_Scoped_Handler(t *testing.T) {
+	defer cleanup()
+	factory := &disposableHandlerFactory{}
+	require.NoError(t, RegisterRequestHandlerFactory(factory.requestFactory(), WithLifetime(Scoped)))
+	err := RegisterRequestHandlerContextFactory(func(ctx context.Context) (RequestHandler[*RequestTest2, *ResponseTest2], error) {
+		if _, err := Send[*RequestTest, *ResponseTest](ctx, &RequestTest{}); err != nil {
+			return nil, err
+		}
+		return &nestedSendingHandler{times: 1}, nil
+	}, WithLifetime(Scoped))
+	require.NoError(t, err)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := Send[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{})
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		require.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the factory of a scoped handler should not hold the scope while building it")
+	}
+	assert.Equal(t, 1, factory.built, "the factory and the handler should share the scoped handler")
+}
+
+func Test_NewScope_Should_Span_Multiple_Calls_Until_Closed(t *testing.T) {
+	defer cleanup()
+	factory := &disposableHandlerFactory{}
+	require.NoError(t, RegisterRequestHandlerFactory(factory.requestFactory(), WithLifetime(Scoped)))
+	require.NoError(t, RegisterNotificationHandlerFactory(factory.notificationFactory(), WithLifetime(Scoped)))
+
+	ctx, scope := NewScope(context.Background())
+	_, err := Send[*RequestTest, *ResponseTest](ctx, &RequestTest{})
+	require.NoError(t, err)
+	_, err = Send[*RequestTest, *ResponseTest](ctx, &RequestTest{})
+	require.NoError(t, err)
+	require.NoError(t, Publish(ctx, &NotificationTest{}))
+	require.NoError(t, Publish(ctx, &NotificationTest{}))
+
+	assert.Equal(t, 2, factory.built, "one request handler and one notification handler should be built for the scope")
+	assert.Equal(t, 0, factory.closed, "handlers should not be disposed before the scope is closed")
+
+	require.NoError(t, scope.Close())
+	assert.Equal(t, 2, factory.closed)
+
+	_, err = Send[*RequestTest, *ResponseTest](ctx, &RequestTest{})
+	assert.ErrorContains(t, err, "scope is closed")
+}
+
+func Test_Send_Should_Not_Allocate_Scope_If_Unused(t *testing.T) {
+	if raceEnabled {
+		t.Skip("allocations are not measured under the race detector")
+	}
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+
+	allocs := sendAllocs(t, context.Background(), &RequestTest{Data: "test"})
+
+	assert.LessOrEqual(t, allocs, 2.0, "the call context and the response of the handler only")
+}
+
+func Test_Send_Should_Return_Error_If_Scope_Disposal_Fails(t *testing.T) {
+	defer cleanup()
+	factory := &disposableHandlerFactory{closeErr: errors.New("close failed")}
+	require.NoError(t, RegisterRequestHandlerFactory(factory.requestFactory(), WithLifetime(Scoped)))
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "scope disposal error: close failed")
+}
+
+func Test_RequestHandlerContextFactory_Should_Build_Handler_From_Request_Context(t *testing.T) {
+	defer cleanup()
+	type tenantKey struct{}
+	var tenant interface{}
+	err := RegisterRequestHandlerContextFactory(func(ctx context.Context) (RequestHandler[*RequestTest, *ResponseTest], error) {
+		tenant = ctx.Value(tenantKey{})
+		return &echoRequestHandler{}, nil
+	})
+	require.NoError(t, err)
+
+	ctx := context.WithValue(context.Background(), tenantKey{}, "acme")
+	response, err := Send[*RequestTest, *ResponseTest](ctx, &RequestTest{Data: "test"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+	assert.Equal(t, "acme", tenant)
+}
+
+func Test_Send_Should_Return_ErrHandlerConstruction_If_Factory_Fails(t *testing.T) {
+	defer cleanup()
+	poolErr := errors.New("pool exhausted")
+	err := RegisterRequestHandlerContextFactory(func(ctx context.Context) (RequestHandler[*RequestTest, *ResponseTest], error) {
+		return nil, poolErr
+	})
+	require.NoError(t, err)
+
+	_, err = Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+
+	assert.ErrorIs(t, err, ErrHandlerConstruction)
+	assert.ErrorIs(t, err, poolErr)
+	assert.EqualError(t, err, "handler construction failed: pool exhausted")
+}
+
+func Test_Publish_Should_Return_ErrHandlerConstruction_If_Factory_Fails(t *testing.T) {
+	defer cleanup()
+	err := RegisterNotificationHandlerContextFactory(func(ctx context.Context) (NotificationHandler[*NotificationTest], error) {
+		return nil, errors.New("pool exhausted")
+	})
+	require.NoError(t, err)
+
+	err = Publish(context.Background(), &NotificationTest{})
+
+	assert.ErrorIs(t, err, ErrHandlerConstruction)
+}
+
+func Test_Singleton_ContextFactory_Should_Retry_After_Failure(t *testing.T) {
+	defer cleanup()
+	calls := 0
+	err := RegisterRequestHandlerContextFactory(func(ctx context.Context) (RequestHandler[*RequestTest, *ResponseTest], error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("not ready")
+		}
+		return &echoRequestHandler{}, nil
+	}, WithLifetime(Singleton))
+	require.NoError(t, err)
+
+	_, err = Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	assert.ErrorIs(t, err, ErrHandlerConstruction)
+
+	sendTimes(t, 2)
+	assert.Equal(t, 2, calls, "singleton should be built once after the failed attempt")
+}
+
+func sendTimes(t *testing.T, times int) {
+	for i := 0; i < times; i++ {
+		_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+		require.NoError(t, err)
+	}
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type disposableHandlerFactory struct {
+	built    int
+	closed   int
+	closeErr error
+}
+
+func (f *disposableHandlerFactory) requestFactory() RequestHandlerFactory[*RequestTest, *ResponseTest] {
+	return func() RequestHandler[*RequestTest, *ResponseTest] {
+		f.built++
+		return &disposableHandler{factory: f}
+	}
+}
+
+func (f *disposableHandlerFactory) notificationFactory() NotificationHandlerFactory[*NotificationTest] {
+	return func() NotificationHandler[*NotificationTest] {
+		f.built++
+		return &disposableNotificationHandler{factory: f}
+	}
+}
+
+type disposableHandler struct {
+	factory *disposableHandlerFactory
+}
+
+func (c *disposableHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	return &ResponseTest{}, nil
+}
+
+func (c *disposableHandler) Close() error {
+	c.factory.closed++
+
+	return c.factory.closeErr
+}
+
+type disposableNotificationHandler struct {
+	factory *disposableHandlerFactory
+}
+
+func (c *disposableNotificationHandler) Handle(ctx context.Context, notification *NotificationTest) error {
+	return nil
+}
+
+func (c *disposableNotificationHandler) Close() error {
+	c.factory.closed++
+
+	return c.factory.closeErr
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type nestedSendingHandler struct {
+	times int
+}
+
+func (c *nestedSendingHandler) Handle(ctx context.Context, request *RequestTest2) (*ResponseTest2, error) {
+	for i := 0; i < c.times; i++ {
+		if _, err := Send[*RequestTest, *ResponseTest](ctx, &RequestTest{}); err != nil {
+			return nil, err
+		}
+	}
+
+	return &ResponseTest2{}, nil
+}
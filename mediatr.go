@@ -10,21 +10,40 @@ package mediatr
 import (
 	"context"
 	"reflect"
-	"sync"
+	"runtime/debug"
+	"slices"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 )
 
 // RequestHandlerFunc is a continuation function used in pipeline behaviors.
 // It represents the next handler in the pipeline chain.
-type RequestHandlerFunc func(ctx context.Context) (interface{}, error)
+// A behavior can pass a replacement request, of the same type as the sent request, that is used
+// by the rest of the pipeline and the handler instead of the original one:
+//
+//	normalized := *request.(*MyRequest)
+//	normalized.Name = strings.TrimSpace(normalized.Name)
+//	return next(ctx, &normalized)
+type RequestHandlerFunc func(ctx context.Context, request ...interface{}) (interface{}, error)
 
 // PipelineBehavior defines middleware-like components that can intercept requests.
 // Implement this interface to add cross-cutting concerns like logging, validation, etc.
+// The next function fails if it is called after the Send call returned.
 type PipelineBehavior interface {
 	Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error)
 }
 
+// NotificationHandlerFunc is the continuation of a notification pipeline behavior, it calls the next behavior
+// or, after the last one, the handlers of the notification.
+type NotificationHandlerFunc func(ctx context.Context) error
+
+// NotificationPipelineBehavior intercepts every Publish call, including the notifications without handlers,
+// e.g. for recording or tracing notifications. The next function must not be called after Handle returns.
+type NotificationPipelineBehavior interface {
+	Handle(ctx context.Context, notification interface{}, next NotificationHandlerFunc) error
+}
+
 // RequestHandler handles a specific request type and returns a response.
 // Implement this interface for your request handlers.
 //
@@ -48,17 +67,17 @@ type NotificationHandler[TNotification any] interface {
 	Handle(ctx context.Context, notification TNotification) error
 }
 
+// RequestHandlerContextFactory creates request handlers from the context of the request,
+// e.g. for reading the tenant or the transaction of the request. A factory error is returned
+// from Send as an error matching ErrHandlerConstruction.
+type RequestHandlerContextFactory[TRequest any, TResponse any] func(ctx context.Context) (RequestHandler[TRequest, TResponse], error)
+
 // NotificationHandlerFactory creates new instances of notification handlers.
 type NotificationHandlerFactory[TNotification any] func() NotificationHandler[TNotification]
 
-var (
-	requestHandlersRegistrations      sync.Map // map[reflect.Type]interface{}
-	notificationHandlersRegistrations sync.Map // map[reflect.Type][]interface{}
-	pipelineBehaviors                 []PipelineBehavior
-
-	notificationHandlerMutex sync.Mutex
-	pipelineMutex            sync.RWMutex
-)
+// NotificationHandlerContextFactory creates notification handlers from the context of the notification.
+// A factory error is returned from Publish as an error matching ErrHandlerConstruction.
+type NotificationHandlerContextFactory[TNotification any] func(ctx context.Context) (NotificationHandler[TNotification], error)
 
 // Unit represents a void return type, used for handlers that don't return data.
 type Unit struct{}
@@ -70,44 +89,96 @@ type Unit struct{}
 //
 //	err := mediatr.RegisterRequestHandler[*MyRequest, *MyResponse](&MyHandler{})
 func RegisterRequestHandler[TRequest any, TResponse any](handler RequestHandler[TRequest, TResponse]) error {
-	return registerRequestHandler[TRequest, TResponse](handler)
+	return registerRequestHandler[TRequest, TResponse](newInstanceRegistration(handler))
 }
 
 // RegisterRequestHandlerFactory registers a factory that creates request handlers.
 // Useful for stateful handlers that need fresh instances per request.
-func RegisterRequestHandlerFactory[TRequest any, TResponse any](factory RequestHandlerFactory[TRequest, TResponse]) error {
-	return registerRequestHandler[TRequest, TResponse](factory)
+// The factory is invoked for every request, unless another lifetime is set with WithLifetime.
+func RegisterRequestHandlerFactory[TRequest any, TResponse any](
+	factory RequestHandlerFactory[TRequest, TResponse],
+	opts ...RegistrationOption,
+) error {
+	return registerRequestHandler[TRequest, TResponse](newFactoryRegistration(factory, func(context.Context) (interface{}, error) {
+		return factory(), nil
+	}, opts))
+}
+
+// RegisterRequestHandlerContextFactory registers a factory that creates request handlers from the request context.
+// The factory is invoked for every request, unless another lifetime is set with WithLifetime.
+// A Singleton factory receives the context of the first request.
+//
+// Example:
+//
+//	err := mediatr.RegisterRequestHandlerContextFactory(func(ctx context.Context) (mediatr.RequestHandler[*MyRequest, *MyResponse], error) {
+//	    tx, err := db.BeginTx(ctx, nil)
+//	    if err != nil {
+//	        return nil, err
+//	    }
+//	    return &MyHandler{tx: tx}, nil
+//	})
+func RegisterRequestHandlerContextFactory[TRequest any, TResponse any](
+	factory RequestHandlerContextFactory[TRequest, TResponse],
+	opts ...RegistrationOption,
+) error {
+	return registerRequestHandler[TRequest, TResponse](newFactoryRegistration(factory, func(ctx context.Context) (interface{}, error) {
+		return factory(ctx)
+	}, opts))
 }
 
 // RegisterRequestPipelineBehaviors registers middleware behaviors that wrap request handlers.
 // Behaviors are executed in registration order (first registered runs first).
-// Returns error if any behavior is already registered.
+// Returns error, without registering any of them, if any behavior is already registered, a behavior of the same type
+// or, for a PipelineBehaviorFunc, the same function.
 func RegisterRequestPipelineBehaviors(behaviours ...PipelineBehavior) error {
-	pipelineMutex.Lock()
-	defer pipelineMutex.Unlock()
-
-	for _, behavior := range behaviours {
-		behaviorType := reflect.TypeOf(behavior)
-		for _, existing := range pipelineBehaviors {
-			if reflect.TypeOf(existing) == behaviorType {
-				return errors.New("behavior already registered")
+	return updateRegistry(func(next *registrySnapshot) error {
+		for _, behavior := range behaviours {
+			if err := next.addPipelineBehavior(behavior); err != nil {
+				return err
 			}
 		}
-		pipelineBehaviors = append(pipelineBehaviors, behavior)
-	}
+		return nil
+	})
+}
 
-	return nil
+// RegisterNotificationPipelineBehaviors registers behaviors that wrap the Publish calls.
+// Behaviors are executed in registration order (first registered runs first).
+// Returns error, without registering any of them, if a behavior of the same type is already registered.
+//
+// Example:
+//
+//	err := mediatr.RegisterNotificationPipelineBehaviors(&NotificationLoggingBehavior{})
+func RegisterNotificationPipelineBehaviors(behaviors ...NotificationPipelineBehavior) error {
+	return updateRegistry(func(next *registrySnapshot) error {
+		for _, behavior := range behaviors {
+			if err := next.addNotificationBehavior(behavior); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
 }
 
 // RegisterNotificationHandler registers a handler for notifications of specific type.
 // Multiple handlers can be registered for the same notification type.
 func RegisterNotificationHandler[TEvent any](handler NotificationHandler[TEvent]) error {
-	return registerNotificationHandler[TEvent](handler)
+	return registerNotificationHandler[TEvent](newInstanceRegistration(handler))
 }
 
 // RegisterNotificationHandlerFactory registers a factory that creates notification handlers.
-func RegisterNotificationHandlerFactory[TEvent any](factory NotificationHandlerFactory[TEvent]) error {
-	return registerNotificationHandler[TEvent](factory)
+// The factory is invoked for every notification, unless another lifetime is set with WithLifetime.
+func RegisterNotificationHandlerFactory[TEvent any](factory NotificationHandlerFactory[TEvent], opts ...RegistrationOption) error {
+	return registerNotificationHandler[TEvent](newFactoryRegistration(factory, func(context.Context) (interface{}, error) {
+		return factory(), nil
+	}, opts))
+}
+
+// RegisterNotificationHandlerContextFactory registers a factory that creates notification handlers from the notification context.
+// The factory is invoked for every notification, unless another lifetime is set with WithLifetime.
+func RegisterNotificationHandlerContextFactory[TEvent any](factory NotificationHandlerContextFactory[TEvent], opts ...RegistrationOption) error {
+	return registerNotificationHandler[TEvent](newFactoryRegistration(factory, func(ctx context.Context) (interface{}, error) {
+		return factory(ctx)
+	}, opts))
 }
 
 // RegisterNotificationHandlers registers multiple handlers for a notification type.
@@ -144,42 +215,80 @@ func RegisterNotificationHandlersFactories[TEvent any](factories ...Notification
 }
 
 // Send dispatches a request to its registered handler and returns the response.
-// Executes all registered pipeline behaviors in order.
+// Executes all registered pipeline behaviors in order, except the ones skipped with SkipBehavior.
+// Begins a Scope for the call if ctx doesn't have one, and closes it when the call returns.
 // Returns error if:
-// - No handler is registered for the request
+// - No handler is registered for the request, nor resolved by the HandlerResolver
 // - Handler returns an error
 // - Any pipeline behavior returns an error
+// - Closing the scope fails
 //
 // Example:
 //
 //	response, err := mediatr.Send[*MyRequest, *MyResponse](ctx, &MyRequest{})
-func Send[TRequest any, TResponse any](ctx context.Context, request TRequest) (TResponse, error) {
-	requestType := reflect.TypeOf(request)
-
-	handler, ok := requestHandlersRegistrations.Load(requestType)
-	if !ok {
+//	response, err := mediatr.Send[*MyRequest, *MyResponse](ctx, &MyRequest{}, mediatr.WithTimeout(time.Second))
+func Send[TRequest any, TResponse any](ctx context.Context, request TRequest, opts ...Option) (response TResponse, err error) {
+	slot, err := loadRequestSlot[TRequest, TResponse](ctx, request)
+	if err != nil {
+		return *new(TResponse), err
+	}
+	if slot.registration == nil {
 		return *new(TResponse), errors.Errorf("no handler for request %T", request)
 	}
 
-	pipelineMutex.RLock()
-	behaviors := make([]PipelineBehavior, len(pipelineBehaviors))
-	copy(behaviors, pipelineBehaviors)
-	pipelineMutex.RUnlock()
+	call, options := applyCallOptions(ctx, opts)
+	ctx = call
+	chain := slot.pipeline
+	if len(options.skippedBehaviors) > 0 {
+		chain = compilePipeline[TRequest, TResponse](options.filterBehaviors(slices.Clone(chain.behaviors)))
+	}
 
-	handlerValue, ok := buildRequestHandler[TRequest, TResponse](handler)
-	if !ok {
-		return *new(TResponse), errors.Errorf("invalid handler for request %T", request)
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if closeErr := call.closeScope(); closeErr != nil && err == nil {
+			response, err = *new(TResponse), errors.Wrap(closeErr, "scope disposal error")
+		}
+	}()
+
+	handlerValue := slot.handler
+	if handlerValue == nil {
+		handlerValue, err = buildRequestHandler[TRequest, TResponse](ctx, slot.registration)
+		if err != nil {
+			return *new(TResponse), err
+		}
+	}
+
+	if observer := loadObserver(); observer != nil {
+		complete := observer.Sent(ctx, request)
+		response, err = dispatchRequest(ctx, chain, handlerValue, request)
+		complete(response, err)
+		return response, err
 	}
 
-	if len(behaviors) > 0 {
-		result, err := buildPipeline(behaviors, handlerValue, request)(ctx)
+	return dispatchRequest(ctx, chain, handlerValue, request)
+}
+
+// dispatchRequest passes the request through the pipeline behaviors to the handler
+func dispatchRequest[TRequest any, TResponse any](
+	ctx context.Context,
+	chain *pipeline[TRequest, TResponse],
+	handler RequestHandler[TRequest, TResponse],
+	request TRequest,
+) (TResponse, error) {
+	if len(chain.behaviors) > 0 {
+		result, err := chain.handle(ctx, handler, request)
 		if err != nil {
 			return *new(TResponse), errors.Wrap(err, "pipeline error")
 		}
 		return result.(TResponse), nil
 	}
 
-	response, err := handlerValue.Handle(ctx, request)
+	response, err := handler.Handle(ctx, request)
 	if err != nil {
 		return *new(TResponse), errors.Wrap(err, "handler error")
 	}
@@ -190,6 +299,9 @@ func Send[TRequest any, TResponse any](ctx context.Context, request TRequest) (T
 // Publish broadcasts a notification to all registered handlers.
 // All handlers are executed, even if some return errors.
 // Returns the first error encountered, if any.
+// Handlers receive the notification Metadata, caused by the call whose handler published it.
+// Notification pipeline behaviors run around the handlers, even if the notification has no handlers.
+// Begins a Scope for the call if ctx doesn't have one, and closes it when the call returns.
 //
 // Example:
 //
@@ -204,20 +316,67 @@ func Send[TRequest any, TResponse any](ctx context.Context, request TRequest) (T
 //	// Publish
 //	err := mediatr.Publish(ctx, OrderShipped{OrderID: "123"})
 //	if err != nil { /* handle error */ }
-func Publish[TNotification any](ctx context.Context, notification TNotification) error {
-	eventType := reflect.TypeOf(notification)
+func Publish[TNotification any](ctx context.Context, notification TNotification, opts ...Option) (err error) {
+	slot, err := loadNotificationSlot(ctx, notification)
+	observer := loadObserver()
+	if err != nil || (len(slot.registrations) == 0 && len(slot.behaviors) == 0 && observer == nil) {
+		return err
+	}
 
-	handlers, ok := notificationHandlersRegistrations.Load(eventType)
-	if !ok {
-		return nil
+	call, options := applyCallOptions(ctx, opts)
+	ctx = call
+	if options.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if closeErr := call.closeScope(); closeErr != nil && err == nil {
+			err = errors.Wrap(closeErr, "scope disposal error")
+		}
+	}()
+
+	if observer != nil {
+		complete := observer.Published(ctx, notification)
+		err = publishNotification(ctx, slot, notification, options.parallelHandlers)
+		complete(err)
+		return err
 	}
 
-	handlerList := handlers.([]interface{})
+	return publishNotification(ctx, slot, notification, options.parallelHandlers)
+}
+
+// publishNotification passes the notification through the notification pipeline behaviors to the handlers
+func publishNotification[TNotification any](
+	ctx context.Context,
+	slot *notificationSlot[TNotification],
+	notification TNotification,
+	parallel bool,
+) error {
+	if len(slot.behaviors) > 0 {
+		return publishThroughBehaviors(ctx, slot.behaviors, notification, func(ctx context.Context) error {
+			return publishToHandlers(ctx, slot, notification, parallel)
+		})
+	}
+
+	return publishToHandlers(ctx, slot, notification, parallel)
+}
+
+func publishToHandlers[TNotification any](
+	ctx context.Context,
+	slot *notificationSlot[TNotification],
+	notification TNotification,
+	parallel bool,
+) error {
+	if parallel {
+		return publishToHandlersInParallel(ctx, slot, notification)
+	}
 
-	for _, handler := range handlerList {
-		handlerValue, ok := buildNotificationHandler[TNotification](handler)
-		if !ok {
-			return errors.Errorf("invalid handler type for notification %T", notification)
+	for i := range slot.registrations {
+		handlerValue, err := slot.handler(ctx, i)
+		if err != nil {
+			return err
 		}
 		if err := handlerValue.Handle(ctx, notification); err != nil {
 			return errors.Wrap(err, "notification handler failed")
@@ -227,113 +386,160 @@ func Publish[TNotification any](ctx context.Context, notification TNotification)
 	return nil
 }
 
+// publishToHandlersInParallel runs each handler as a task of the executor, and waits for all of them
+func publishToHandlersInParallel[TNotification any](
+	ctx context.Context,
+	slot *notificationSlot[TNotification],
+	notification TNotification,
+) error {
+	handlers := make([]NotificationHandler[TNotification], len(slot.registrations))
+	for i := range slot.registrations {
+		handlerValue, err := slot.handler(ctx, i)
+		if err != nil {
+			return err
+		}
+		handlers[i] = handlerValue
+	}
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	errs := make([]error, len(handlers))
+	remaining := atomic.Int64{}
+	remaining.Store(int64(len(handlers)))
+	done := make(chan struct{})
+	executor := currentExecutor()
+	for i, handlerValue := range handlers {
+		executor.Go(func() {
+			defer func() {
+				if r := recover(); r != nil {
+					errs[i] = &PanicError{Value: r, Stack: debug.Stack()}
+				}
+				if remaining.Add(-1) == 0 {
+					close(done)
+				}
+			}()
+
+			errs[i] = handlerValue.Handle(ctx, notification)
+		})
+	}
+
+	// the handlers may use the scope of the call, which is closed when Publish returns, so they are waited even if ctx is done
+	_ = executor.Wait(context.Background(), done)
+	for _, err := range errs {
+		if err != nil {
+			return errors.Wrap(err, "notification handler failed")
+		}
+	}
+
+	return nil
+}
+
 // ClearRequestRegistrations removes all registered request handlers.
 // Useful for testing scenarios.
 func ClearRequestRegistrations() {
-	requestHandlersRegistrations = sync.Map{}
+	_ = updateRegistry(func(next *registrySnapshot) error {
+		clear(next.requests)
+		return nil
+	})
 }
 
 // ClearNotificationRegistrations removes all registered notification handlers.
 func ClearNotificationRegistrations() {
-	notificationHandlersRegistrations = sync.Map{}
+	_ = updateRegistry(func(next *registrySnapshot) error {
+		clear(next.notifications)
+		return nil
+	})
 }
 
-// ClearPipelineBehaviors removes all registered pipeline behaviors.
+// ClearPipelineBehaviors removes all registered request and notification pipeline behaviors.
 func ClearPipelineBehaviors() {
-	pipelineMutex.Lock()
-	defer pipelineMutex.Unlock()
-	pipelineBehaviors = []PipelineBehavior{}
+	_ = updateRegistry(func(next *registrySnapshot) error {
+		next.behaviors = nil
+		next.notificationBehaviors = nil
+		return nil
+	})
 }
 
-func registerRequestHandler[TRequest any, TResponse any](handler any) error {
+func registerRequestHandler[TRequest any, TResponse any](registration *handlerRegistration) error {
 	var request TRequest
-	requestType := reflect.TypeOf(request)
 
-	if _, loaded := requestHandlersRegistrations.LoadOrStore(requestType, handler); loaded {
-		return errors.Errorf("handler already exists for type %s", requestType.String())
-	}
-	return nil
+	return storeRequestRegistration(reflect.TypeOf(request), withResponseType[TResponse](registration))
 }
 
-func registerNotificationHandler[TEvent any](handler any) error {
-	var event TEvent
-	eventType := reflect.TypeOf(event)
+// withResponseType records the response type of a request handler registration
+func withResponseType[TResponse any](registration *handlerRegistration) *handlerRegistration {
+	registration.responseType = reflect.TypeOf((*TResponse)(nil)).Elem()
 
-	// Uses separate mutex for slice modifications and adding new item with LoadOrStore if not exists for prevention conflict with concurrent goroutines
-	notificationHandlerMutex.Lock()
-	defer notificationHandlerMutex.Unlock()
+	return registration
+}
 
-	// If not found, stores a new slice with the handler as its first element, If found, returns the existing slice of handlers.
-	actual, loaded := notificationHandlersRegistrations.LoadOrStore(eventType, []interface{}{handler})
-	if !loaded {
-		return nil
-	}
+func registerNotificationHandler[TEvent any](registration *handlerRegistration) error {
+	var event TEvent
 
-	handlers := actual.([]interface{})
+	return storeNotificationRegistration(reflect.TypeOf(event), registration)
+}
 
-	newHandlers := append(handlers, handler)
-	notificationHandlersRegistrations.Store(eventType, newHandlers)
+func storeRequestRegistration(requestType reflect.Type, registration *handlerRegistration) error {
+	return updateRegistry(func(next *registrySnapshot) error {
+		return next.addRequestHandler(requestType, registration)
+	})
+}
 
-	return nil
+func storeNotificationRegistration(eventType reflect.Type, registration *handlerRegistration) error {
+	return updateRegistry(func(next *registrySnapshot) error {
+		return next.addNotificationHandler(eventType, registration)
+	})
 }
 
-func buildRequestHandler[TRequest any, TResponse any](handler any) (RequestHandler[TRequest, TResponse], bool) {
-	handlerValue, ok := handler.(RequestHandler[TRequest, TResponse])
-	if !ok {
-		factory, ok := handler.(RequestHandlerFactory[TRequest, TResponse])
-		if !ok {
-			return nil, false
-		}
+// loadRequestRegistration returns the registration of a request type, consulting the handler resolver for unregistered types
+func loadRequestRegistration(ctx context.Context, requestType reflect.Type) (*handlerRegistration, error) {
+	return loadRegistry().requestRegistration(ctx, requestType)
+}
+
+// loadNotificationRegistrations returns the registrations of a notification type, consulting the handler resolver for unregistered types
+func loadNotificationRegistrations(ctx context.Context, notificationType reflect.Type) ([]*handlerRegistration, error) {
+	return loadRegistry().notificationRegistrations(ctx, notificationType)
+}
 
-		return factory(), true
+func buildRequestHandler[TRequest any, TResponse any](
+	ctx context.Context,
+	registration *handlerRegistration,
+) (RequestHandler[TRequest, TResponse], error) {
+	handler, err := registration.resolve(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	return handlerValue, true
-}
+	if untyped, ok := handler.(untypedRequestHandler); ok {
+		return untypedRequestHandlerAdapter[TRequest, TResponse]{handler: untyped}, nil
+	}
 
-func buildNotificationHandler[TNotification any](handler any) (NotificationHandler[TNotification], bool) {
-	handlerValue, ok := handler.(NotificationHandler[TNotification])
+	handlerValue, ok := handler.(RequestHandler[TRequest, TResponse])
 	if !ok {
-		factory, ok := handler.(NotificationHandlerFactory[TNotification])
-		if !ok {
-			return nil, false
-		}
-
-		return factory(), true
+		return nil, errors.Errorf("invalid handler for request %s", reflect.TypeOf((*TRequest)(nil)).Elem())
 	}
 
-	return handlerValue, true
+	return handlerValue, nil
 }
 
-// buildPipeline constructs the middleware chain
-func buildPipeline[TRequest any, TResponse any](
-	behaviors []PipelineBehavior,
-	handler RequestHandler[TRequest, TResponse],
-	request TRequest,
-) RequestHandlerFunc {
-	reversed := reverseBehaviors(behaviors)
-
-	chain := func(ctx context.Context) (interface{}, error) {
-		return handler.Handle(ctx, request)
+func buildNotificationHandler[TNotification any](
+	ctx context.Context,
+	registration *handlerRegistration,
+) (NotificationHandler[TNotification], error) {
+	handler, err := registration.resolve(ctx)
+	if err != nil {
+		return nil, err
 	}
 
-	// Build the pipeline by wrapping each behavior
-	for _, behavior := range reversed {
-		currentBehavior := behavior // capture for closure
-		next := chain
-		chain = func(ctx context.Context) (interface{}, error) {
-			return currentBehavior.Handle(ctx, request, next)
-		}
+	if untyped, ok := handler.(untypedNotificationHandler); ok {
+		return untypedNotificationHandlerAdapter[TNotification]{handler: untyped}, nil
 	}
 
-	return chain
-}
-
-// reverseBehaviors reverses the order of pipeline behaviors
-func reverseBehaviors(behaviors []PipelineBehavior) []PipelineBehavior {
-	reversed := make([]PipelineBehavior, len(behaviors))
-	for i := range behaviors {
-		reversed[len(behaviors)-1-i] = behaviors[i]
+	handlerValue, ok := handler.(NotificationHandler[TNotification])
+	if !ok {
+		return nil, errors.Errorf("invalid handler type for notification %s", reflect.TypeOf((*TNotification)(nil)).Elem())
 	}
-	return reversed
+
+	return handlerValue, nil
 }
@@ -2,6 +2,8 @@ package mediatr
 
 import (
 	"context"
+	"fmt"
+	"reflect"
 	"testing"
 )
 
@@ -46,3 +48,119 @@ func Benchmark_Publish(b *testing.B) {
 		}
 	}
 }
+
+func Benchmark_Send_Dispatch(b *testing.B) {
+	defer cleanup()
+	if err := RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}); err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	request := &RequestTest{Data: "test"}
+
+	b.Run("Reflection", func(b *testing.B) {
+		dispatchSlotsEnabled = false
+		defer func() { dispatchSlotsEnabled = true }()
+		benchmarkSend(b, ctx, request)
+	})
+	b.Run("Slots", func(b *testing.B) {
+		benchmarkSend(b, ctx, request)
+	})
+}
+
+func Benchmark_Publish_Dispatch(b *testing.B) {
+	defer cleanup()
+	if err := RegisterNotificationHandlers[*NotificationTest](&dispatchCountingHandler{}, &dispatchCountingHandler{}); err != nil {
+		b.Fatal(err)
+	}
+	ctx := context.Background()
+	notification := &NotificationTest{Data: "test"}
+
+	b.Run("Reflection", func(b *testing.B) {
+		dispatchSlotsEnabled = false
+		defer func() { dispatchSlotsEnabled = true }()
+		benchmarkPublish(b, ctx, notification)
+	})
+	b.Run("Slots", func(b *testing.B) {
+		benchmarkPublish(b, ctx, notification)
+	})
+}
+
+func benchmarkSend(b *testing.B, ctx context.Context, request *RequestTest) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if _, err := Send[*RequestTest, *ResponseTest](ctx, request); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func benchmarkPublish(b *testing.B, ctx context.Context, notification *NotificationTest) {
+	b.ReportAllocs()
+	for b.Loop() {
+		if err := Publish(ctx, notification); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func Benchmark_Resolve_Request_Handler(b *testing.B) {
+	registrations := map[string]func() error{
+		"Instance": func() error {
+			return RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{})
+		},
+		"Convention": func() error {
+			return RegisterHandlersFrom(&conventionHandlers{})
+		},
+	}
+	ctx := context.Background()
+	request := &RequestTest{Data: "test"}
+
+	for _, name := range []string{"Instance", "Convention"} {
+		b.Run(name, func(b *testing.B) {
+			defer cleanup()
+			if err := registrations[name](); err != nil {
+				b.Fatal(err)
+			}
+
+			b.Run("Reflection", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					registration, err := loadRequestRegistration(ctx, reflect.TypeOf(request))
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := buildRequestHandler[*RequestTest, *ResponseTest](ctx, registration); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("Slots", func(b *testing.B) {
+				b.ReportAllocs()
+				for b.Loop() {
+					if slot, err := loadRequestSlot[*RequestTest, *ResponseTest](ctx, request); err != nil || slot.handler == nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+func Benchmark_Send_Pipeline(b *testing.B) {
+	ctx := context.Background()
+	request := &RequestTest{Data: "test"}
+
+	for _, count := range []int{0, 1, 5} {
+		b.Run(fmt.Sprintf("Behaviors=%d", count), func(b *testing.B) {
+			defer cleanup()
+			if err := RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}); err != nil {
+				b.Fatal(err)
+			}
+			if err := RegisterRequestPipelineBehaviors(forwardingBehaviors()[:count]...); err != nil {
+				b.Fatal(err)
+			}
+
+			benchmarkSend(b, ctx, request)
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 	"sync"
 	"testing"
 
@@ -46,6 +47,8 @@ func TestRunner(t *testing.T) {
 		test.Test_Register_Behaviours_Should_Register_Behaviours_In_The_Registry_Correctly()
 		test.Test_Register_Duplicate_Behaviours_Should_Throw_Error()
 		test.Test_Send_Should_Dispatch_Request_To_Handler_And_Get_Response_With_Pipeline()
+		test.Test_Send_Should_Pass_Replaced_Request_To_Downstream_Behaviors_And_Handler()
+		test.Test_Send_Should_Return_Error_If_Replaced_Request_Has_Different_Type()
 	})
 }
 
@@ -56,18 +59,15 @@ type MediatRTests struct {
 // Helper functions for tests
 func countRequestHandlers() int {
 	count := 0
-	requestHandlersRegistrations.Range(func(_, _ interface{}) bool {
+	for range Describe().Requests() {
 		count++
-		return true
-	})
+	}
 	return count
 }
 
 func countNotificationHandlers(eventType reflect.Type) int {
-	if handlers, ok := notificationHandlersRegistrations.Load(eventType); ok {
-		return len(handlers.([]interface{}))
-	}
-	return 0
+	notification, _ := Describe().Notification(eventType.String())
+	return len(notification.Handlers)
 }
 
 func (t *MediatRTests) Test_Send_Should_Dispatch_Request_To_Factory() {
@@ -228,6 +228,40 @@ func (t *MediatRTests) Test_Send_Should_Dispatch_Request_To_Handler_And_Get_Resp
 	assert.Contains(t, testData, "PipelineBehaviourTest2")
 }
 
+func (t *MediatRTests) Test_Send_Should_Pass_Replaced_Request_To_Downstream_Behaviors_And_Handler() {
+	defer cleanup()
+	normalizer := &NormalizerPipelineBehaviourTest{}
+	recorder := &RecorderPipelineBehaviourTest{}
+	err := RegisterRequestPipelineBehaviors(normalizer, recorder)
+	require.NoError(t, err)
+
+	handler := &RequestTestHandler{}
+	errRegister := RegisterRequestHandler[*RequestTest, *ResponseTest](handler)
+	require.NoError(t, errRegister)
+
+	request := &RequestTest{Data: "  test  "}
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), request)
+	require.NoError(t, err)
+
+	assert.Equal(t, "test", response.Data, "handler should receive the replaced request")
+	assert.Equal(t, "test", recorder.request.(*RequestTest).Data, "downstream behavior should receive the replaced request")
+	assert.Equal(t, "  test  ", request.Data, "caller's request should not be modified")
+}
+
+func (t *MediatRTests) Test_Send_Should_Return_Error_If_Replaced_Request_Has_Different_Type() {
+	defer cleanup()
+	err := RegisterRequestPipelineBehaviors(&InvalidReplacementPipelineBehaviourTest{})
+	require.NoError(t, err)
+
+	handler := &RequestTestHandler{}
+	errRegister := RegisterRequestHandler[*RequestTest, *ResponseTest](handler)
+	require.NoError(t, errRegister)
+
+	_, err = Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid replacement request *mediatr.RequestTest2, expected *mediatr.RequestTest")
+}
+
 func (t *MediatRTests) Test_RegisterNotificationHandler_Should_Register_Multiple_Handler_For_Notification() {
 	defer cleanup()
 	handler1 := &NotificationTestHandler{}
@@ -333,7 +367,7 @@ func (t *MediatRTests) Test_Register_Behaviours_Should_Register_Behaviours_In_Th
 		t.Errorf("error registering behaviours: %s", err)
 	}
 
-	count := len(pipelineBehaviors)
+	count := len(loadRegistry().behaviors)
 	assert.Equal(t, 2, count)
 }
 
@@ -525,6 +559,36 @@ func (c *PipelineBehaviourTest2) Handle(ctx context.Context, request interface{}
 	return res, nil
 }
 
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type NormalizerPipelineBehaviourTest struct {
+}
+
+func (c *NormalizerPipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	normalized := *request.(*RequestTest)
+	normalized.Data = strings.TrimSpace(normalized.Data)
+
+	return next(ctx, &normalized)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type RecorderPipelineBehaviourTest struct {
+	request interface{}
+}
+
+func (c *RecorderPipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	c.request = request
+
+	return next(ctx)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type InvalidReplacementPipelineBehaviourTest struct {
+}
+
+func (c *InvalidReplacementPipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	return next(ctx, &RequestTest2{})
+}
+
 // /////////////////////////////////////////////////////////////////////////////////////////////
 func cleanup() {
 	testMutex.Lock()
@@ -535,6 +599,10 @@ func cleanup() {
 	ClearNotificationRegistrations()
 	ClearPipelineBehaviors()
 
+	installMutex.Lock()
+	installedModules = map[string]struct{}{}
+	installMutex.Unlock()
+
 	// Reset test data
 	testData = nil
 }
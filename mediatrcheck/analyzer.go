@@ -0,0 +1,319 @@
+// Package mediatrcheck defines an analyzer that checks the Send calls of a program against its request
+// handler registrations at build time, instead of on the first request.
+//
+// The analyzer finds the instantiations of the registration functions (mediatr.RegisterRequestHandler,
+// mediatr.AddRequestHandler, mediatrfx.RequestHandler and their variants) and of mediatr.Send and
+// mediatr.SendAsync, and reports:
+//
+//   - the registrations of a request type that is already registered
+//   - the Send calls whose response type differs from the response type of the registration
+//   - in main packages, the Send calls of the program whose request type is not registered
+//
+// The calls in test files are not checked against each other, since the tests clear the registrations and
+// register the same types again.
+//
+// Registrations and Send calls are exported as facts, so the checks span the packages of the program.
+// Handlers registered by reflection, with mediatr.RegisterHandlersFrom or a custom mediatr.HandlerResolver,
+// are not visible to the analyzer.
+package mediatrcheck
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"slices"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+)
+
+const (
+	mediatrPath   = "github.com/mehdihadeli/go-mediatr"
+	mediatrfxPath = "github.com/mehdihadeli/go-mediatr/mediatrfx"
+)
+
+// registrationFuncs are the functions registering a request handler, by package path
+var registrationFuncs = map[string][]string{
+	mediatrPath: {
+		"RegisterRequestHandler",
+		"RegisterRequestHandlerFactory",
+		"RegisterRequestHandlerContextFactory",
+		"RegisterRequestHandlerFunc",
+		"AddRequestHandler",
+		"AddRequestHandlerFactory",
+		"AddRequestHandlerFunc",
+	},
+	mediatrfxPath: {
+		"RequestHandler",
+		"ProvideRequestHandler",
+	},
+}
+
+// sendFuncs are the functions sending a request, by package path
+var sendFuncs = map[string][]string{
+	mediatrPath: {"Send", "SendAsync"},
+}
+
+// Analyzer reports the Send calls without registration, with a mismatched response type,
+// and the duplicate registrations of request types.
+var Analyzer = &analysis.Analyzer{
+	Name:      "mediatrcheck",
+	Doc:       "check mediatr Send calls against the request handler registrations",
+	URL:       "https://pkg.go.dev/github.com/mehdihadeli/go-mediatr/mediatrcheck",
+	Run:       run,
+	FactTypes: []analysis.Fact{new(callsFact)},
+}
+
+// Call is a registration or a Send call of a request type. Request and Response are the types qualified by their
+// package paths, RequestName and ResponseName by their package names, for the messages.
+type Call struct {
+	Func         string
+	Request      string
+	Response     string
+	RequestName  string
+	ResponseName string
+	Position     string
+}
+
+// callsFact is the package fact listing the registrations and Send calls of a package and its dependencies,
+// with the keys of the problems already reported
+type callsFact struct {
+	Registrations []Call
+	Sends         []Call
+	Reported      []string
+}
+
+// AFact implements analysis.Fact.
+func (*callsFact) AFact() {}
+
+func (f *callsFact) String() string {
+	return fmt.Sprintf("mediatr(%d registrations, %d sends)", len(f.Registrations), len(f.Sends))
+}
+
+// problem is a broken rule involving calls that can be local to the analyzed package, or not
+type problem struct {
+	key      string
+	subjects []Call
+	message  func(subject Call) string
+}
+
+func run(pass *analysis.Pass) (interface{}, error) {
+	// the main packages synthesized for the tests have no calls, and don't see the registrations of the program
+	if strings.HasSuffix(pass.Pkg.Path(), ".test") {
+		return nil, nil
+	}
+	localRegistrations, localSends, positions := findCalls(pass)
+
+	all := &callsFact{}
+	reported := map[string]bool{}
+	seen := map[string]bool{}
+	for _, imported := range pass.Pkg.Imports() {
+		fact := new(callsFact)
+		if !pass.ImportPackageFact(imported, fact) {
+			continue
+		}
+		all.Registrations = appendUnseen(all.Registrations, fact.Registrations, seen)
+		all.Sends = appendUnseen(all.Sends, fact.Sends, seen)
+		for _, key := range fact.Reported {
+			reported[key] = true
+		}
+	}
+	all.Registrations = append(all.Registrations, localRegistrations...)
+	all.Sends = append(all.Sends, localSends...)
+
+	isMain := pass.Pkg.Name() == "main"
+	for _, p := range findProblems(all, isMain) {
+		if reported[p.key] {
+			continue
+		}
+
+		local := slices.IndexFunc(p.subjects, func(subject Call) bool {
+			_, ok := positions[subject.Position]
+			return ok
+		})
+		switch {
+		case local >= 0:
+			subject := p.subjects[local]
+			pass.Reportf(positions[subject.Position], "%s", p.message(subject))
+		case isMain && len(pass.Files) > 0:
+			subject := p.subjects[0]
+			pass.Reportf(pass.Files[0].Name.Pos(), "%s: %s", subject.Position, p.message(subject))
+		default:
+			continue
+		}
+		reported[p.key] = true
+	}
+
+	for key := range reported {
+		all.Reported = append(all.Reported, key)
+	}
+	slices.Sort(all.Reported)
+	pass.ExportPackageFact(all)
+
+	return nil, nil
+}
+
+// findCalls returns the registrations and Send calls of the package, with the positions of their identifiers
+func findCalls(pass *analysis.Pass) ([]Call, []Call, map[string]token.Pos) {
+	var registrations, sends []Call
+	positions := map[string]token.Pos{}
+
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(node ast.Node) bool {
+			ident, ok := node.(*ast.Ident)
+			if !ok {
+				return true
+			}
+			instance, ok := pass.TypesInfo.Instances[ident]
+			if !ok || instance.TypeArgs.Len() < 2 || hasTypeParams(instance.TypeArgs) {
+				return true
+			}
+			function, ok := pass.TypesInfo.Uses[ident].(*types.Func)
+			if !ok || function.Pkg() == nil {
+				return true
+			}
+
+			call := Call{
+				Func:         function.Pkg().Name() + "." + function.Name(),
+				Request:      types.TypeString(instance.TypeArgs.At(0), nil),
+				Response:     types.TypeString(instance.TypeArgs.At(1), nil),
+				RequestName:  types.TypeString(instance.TypeArgs.At(0), packageName),
+				ResponseName: types.TypeString(instance.TypeArgs.At(1), packageName),
+				Position:     pass.Fset.Position(ident.Pos()).String(),
+			}
+			switch {
+			case slices.Contains(registrationFuncs[function.Pkg().Path()], function.Name()):
+				registrations = append(registrations, call)
+			case slices.Contains(sendFuncs[function.Pkg().Path()], function.Name()):
+				sends = append(sends, call)
+			default:
+				return true
+			}
+			positions[call.Position] = ident.Pos()
+
+			return true
+		})
+	}
+
+	return registrations, sends, positions
+}
+
+// findProblems checks the calls of a package and its dependencies, the Send calls without registration
+// are only problems for a main package, which sees all the registrations of the program.
+// The calls of the tests aren't checked against each other, the tests clear the registrations and register again.
+func findProblems(calls *callsFact, isMain bool) []problem {
+	var problems []problem
+
+	registered := map[string]Call{}
+	for _, registration := range calls.Registrations {
+		first, exists := registered[registration.Request]
+		if !exists {
+			registered[registration.Request] = registration
+			continue
+		}
+		if inTestFile(first) || inTestFile(registration) {
+			continue
+		}
+		problems = append(problems, problem{
+			key:      "duplicate:" + first.Position + "|" + registration.Position,
+			subjects: []Call{registration, first},
+			message: func(subject Call) string {
+				other := first
+				if subject == first {
+					other = registration
+				}
+				return fmt.Sprintf("duplicate registration of request %s, also registered at %s", subject.RequestName, other.Position)
+			},
+		})
+	}
+
+	for _, send := range calls.Sends {
+		registration, exists := registered[send.Request]
+		switch {
+		case exists && registration.Response != send.Response && !(inTestFile(send) && inTestFile(registration)):
+			problems = append(problems, problem{
+				key:      "mismatch:" + send.Position + "|" + registration.Position,
+				subjects: []Call{send, registration},
+				message: func(subject Call) string {
+					if subject == send {
+						return fmt.Sprintf(
+							"request %s is sent for response %s, but its handler is registered with response %s at %s",
+							send.RequestName, send.ResponseName, registration.ResponseName, registration.Position,
+						)
+					}
+					return fmt.Sprintf(
+						"request %s is registered with response %s, but it is sent for response %s at %s",
+						send.RequestName, registration.ResponseName, send.ResponseName, send.Position,
+					)
+				},
+			})
+		case !exists && isMain:
+			problems = append(problems, problem{
+				key:      "missing:" + send.Position,
+				subjects: []Call{send},
+				message: func(subject Call) string {
+					return fmt.Sprintf("request %s is sent, but no handler is registered for it", send.RequestName)
+				},
+			})
+		}
+	}
+
+	return problems
+}
+
+func appendUnseen(calls []Call, imported []Call, seen map[string]bool) []Call {
+	for _, call := range imported {
+		if !seen[call.Position] {
+			seen[call.Position] = true
+			calls = append(calls, call)
+		}
+	}
+
+	return calls
+}
+
+func hasTypeParams(typeArgs *types.TypeList) bool {
+	for i := 0; i < typeArgs.Len(); i++ {
+		if containsTypeParam(typeArgs.At(i)) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func containsTypeParam(t types.Type) bool {
+	switch t := t.(type) {
+	case *types.TypeParam:
+		return true
+	case *types.Pointer:
+		return containsTypeParam(t.Elem())
+	case *types.Slice:
+		return containsTypeParam(t.Elem())
+	case *types.Array:
+		return containsTypeParam(t.Elem())
+	case *types.Map:
+		return containsTypeParam(t.Key()) || containsTypeParam(t.Elem())
+	case *types.Chan:
+		return containsTypeParam(t.Elem())
+	case *types.Named:
+		for i := 0; i < t.TypeArgs().Len(); i++ {
+			if containsTypeParam(t.TypeArgs().At(i)) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// packageName qualifies the types of the messages by the names of their packages, e.g. "*orders.CreateOrder"
+func packageName(pkg *types.Package) string {
+	return pkg.Name()
+}
+
+// inTestFile reports whether a call is in a test file
+func inTestFile(call Call) bool {
+	return strings.Contains(call.Position, "_test.go:")
+}
@@ -0,0 +1,12 @@
+package mediatrcheck_test
+
+import (
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr/mediatrcheck"
+	"golang.org/x/tools/go/analysis/analysistest"
+)
+
+func Test_Analyzer_Should_Report_Send_And_Registration_Problems(t *testing.T) {
+	analysistest.Run(t, analysistest.TestData(), mediatrcheck.Analyzer, "app", "orders", "example.com/go-billing")
+}
@@ -0,0 +1,23 @@
+package main // want package:`mediatr\(5 registrations, 5 sends\)` `.*catalog.go:\d+:\d+: request \*catalog.DeleteProduct is sent, but no handler is registered for it`
+
+import (
+	"context"
+
+	"catalog"
+	"example.com/go-billing"
+	"orders"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+type Ping struct{}
+
+type Pong struct{}
+
+func main() {
+	_ = orders.Register()
+	_ = billing.Register()
+	_, _ = catalog.Get(context.Background())
+	_, _ = mediatr.Send[*orders.PlaceOrder, *orders.OrderPlaced](context.Background(), &orders.PlaceOrder{})
+	_, _ = mediatr.Send[*Ping, *Pong](context.Background(), &Ping{}) // want `request \*main.Ping is sent, but no handler is registered for it`
+}
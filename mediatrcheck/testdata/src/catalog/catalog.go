@@ -0,0 +1,27 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+type GetProduct struct{}
+
+type Product struct{}
+
+type DeleteProduct struct{}
+
+type Deleted struct{}
+
+func Register(r mediatr.Registrar) error {
+	return mediatr.AddRequestHandler[*GetProduct, *Product](r, nil)
+}
+
+func Get(ctx context.Context) (*Product, error) {
+	return mediatr.Send[*GetProduct, *Product](ctx, &GetProduct{})
+}
+
+func Delete(ctx context.Context) (*Deleted, error) {
+	return mediatr.Send[*DeleteProduct, *Deleted](ctx, &DeleteProduct{})
+}
@@ -0,0 +1,16 @@
+package billing // want package:`mediatr\(2 registrations, 0 sends\)`
+
+import (
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+type Invoice struct{}
+
+type Invoiced struct{}
+
+func Register() error {
+	if err := mediatr.RegisterRequestHandler[*Invoice, *Invoiced](nil); err != nil {
+		return err
+	}
+	return mediatr.RegisterRequestHandler[*Invoice, *Invoiced](nil) // want `duplicate registration of request \*billing.Invoice, also registered at .*billing.go:\d+:\d+`
+}
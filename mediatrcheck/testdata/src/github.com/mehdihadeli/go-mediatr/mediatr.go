@@ -0,0 +1,25 @@
+package mediatr
+
+import "context"
+
+type RequestHandler[TRequest any, TResponse any] interface {
+	Handle(ctx context.Context, request TRequest) (TResponse, error)
+}
+
+type Registrar interface{}
+
+func RegisterRequestHandler[TRequest any, TResponse any](handler RequestHandler[TRequest, TResponse]) error {
+	return nil
+}
+
+func RegisterRequestHandlerFunc[TRequest any, TResponse any](fn func(ctx context.Context, request TRequest) (TResponse, error)) error {
+	return RegisterRequestHandler[TRequest, TResponse](nil)
+}
+
+func AddRequestHandler[TRequest any, TResponse any](r Registrar, handler RequestHandler[TRequest, TResponse]) error {
+	return nil
+}
+
+func Send[TRequest any, TResponse any](ctx context.Context, request TRequest) (TResponse, error) {
+	return *new(TResponse), nil
+}
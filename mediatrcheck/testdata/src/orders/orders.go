@@ -0,0 +1,27 @@
+package orders // want package:`mediatr\([35] registrations, 3 sends\)`
+
+import (
+	"context"
+
+	"catalog"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+type PlaceOrder struct{}
+
+type OrderPlaced struct{}
+
+func Register() error {
+	if err := mediatr.RegisterRequestHandlerFunc(func(ctx context.Context, request *PlaceOrder) (*OrderPlaced, error) {
+		return &OrderPlaced{}, nil
+	}); err != nil {
+		return err
+	}
+	return mediatr.RegisterRequestHandler[*catalog.GetProduct, *catalog.Product](nil) // want `duplicate registration of request \*catalog.GetProduct, also registered at .*catalog.go:\d+:\d+`
+}
+
+func Price(ctx context.Context) (*catalog.Product, error) {
+	_, err := mediatr.Send[*catalog.GetProduct, *OrderPlaced](ctx, &catalog.GetProduct{}) // want `request \*catalog.GetProduct is sent for response \*orders.OrderPlaced, but its handler is registered with response \*catalog.Product at .*catalog.go:\d+:\d+`
+	return nil, err
+}
@@ -0,0 +1,15 @@
+package orders
+
+import (
+	"testing"
+
+	"catalog"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+func TestRegister(t *testing.T) {
+	_ = Register()
+	_ = mediatr.RegisterRequestHandler[*catalog.GetProduct, *catalog.Product](nil)
+	_ = mediatr.RegisterRequestHandler[*PlaceOrder, *OrderPlaced](nil)
+}
@@ -0,0 +1,77 @@
+package mediatrfx
+
+import (
+	"reflect"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"go.uber.org/dig"
+)
+
+// ProvideRequestHandler provides the constructor of a request handler to the container, and adds the
+// handler to the resolver. The container builds the handler the first time the request is sent.
+//
+// Example:
+//
+//	err := mediatrfx.ProvideRequestHandler[*CreateProductCommand, *CreateProductCommandResponse](
+//	    container, resolver, NewCreateProductCommandHandler)
+func ProvideRequestHandler[TRequest any, TResponse any](container *dig.Container, resolver *Resolver, constructor interface{}) error {
+	err := container.Provide(constructor, dig.As(new(mediatr.RequestHandler[TRequest, TResponse])))
+	if err != nil {
+		return err
+	}
+
+	resolver.setRequestHandler(typeOf[TRequest](), func() (interface{}, error) {
+		var handler mediatr.RequestHandler[TRequest, TResponse]
+		err := container.Invoke(func(h mediatr.RequestHandler[TRequest, TResponse]) {
+			handler = h
+		})
+
+		return handler, err
+	})
+
+	return nil
+}
+
+// ProvideNotificationHandler provides the constructor of a notification handler to the container, and adds
+// the handler to the resolver. Several handlers can be provided for the same notification type.
+func ProvideNotificationHandler[TNotification any](container *dig.Container, resolver *Resolver, constructor interface{}) error {
+	group := notificationGroup[TNotification]()
+	err := container.Provide(
+		constructor,
+		dig.Group(group),
+		dig.As(new(mediatr.NotificationHandler[TNotification])),
+	)
+	if err != nil {
+		return err
+	}
+
+	resolver.setNotificationHandlers(typeOf[TNotification](), func() ([]interface{}, error) {
+		handlers, err := invokeGroup[mediatr.NotificationHandler[TNotification]](container, group)
+
+		return toInterfaces(handlers), err
+	})
+
+	return nil
+}
+
+// invokeGroup returns the values of a value group, the group name is only known at runtime so the
+// dig.In parameter struct holding the group is built with reflection
+func invokeGroup[T any](container *dig.Container, group string) ([]T, error) {
+	paramType := reflect.StructOf([]reflect.StructField{
+		{Name: "In", Type: reflect.TypeOf(dig.In{}), Anonymous: true},
+		{Name: "Values", Type: reflect.TypeOf([]T{}), Tag: reflect.StructTag(`group:"` + group + `"`)},
+	})
+
+	var values []T
+	function := reflect.MakeFunc(
+		reflect.FuncOf([]reflect.Type{paramType}, nil, false),
+		func(args []reflect.Value) []reflect.Value {
+			values = args[0].Field(1).Interface().([]T)
+			return nil
+		},
+	)
+
+	err := container.Invoke(function.Interface())
+
+	return values, err
+}
@@ -0,0 +1,71 @@
+package mediatrfx
+
+import (
+	"context"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"go.uber.org/fx"
+)
+
+// Module provides the Resolver to an fx application, and sets it as the mediator handler resolver
+// while the application is running.
+//
+// Example:
+//
+//	app := fx.New(
+//	    mediatrfx.Module,
+//	    fx.Provide(repository.NewInMemoryProductRepository),
+//	    mediatrfx.RequestHandler[*CreateProductCommand, *CreateProductCommandResponse](NewCreateProductCommandHandler),
+//	    mediatrfx.NotificationHandler[*ProductCreatedEvent](NewProductCreatedEventHandler),
+//	)
+var Module = fx.Module(
+	"mediatr",
+	fx.Provide(NewResolver),
+	fx.Invoke(func(lifecycle fx.Lifecycle, resolver *Resolver) {
+		lifecycle.Append(fx.Hook{
+			OnStart: func(context.Context) error {
+				mediatr.SetHandlerResolver(resolver)
+				return nil
+			},
+			OnStop: func(context.Context) error {
+				mediatr.SetHandlerResolver(nil)
+				return nil
+			},
+		})
+	}),
+)
+
+// RequestHandler provides the constructor of a request handler to the application, and adds the
+// handler to the resolver. The handler is built by fx when the application is initialized.
+func RequestHandler[TRequest any, TResponse any](constructor interface{}) fx.Option {
+	return fx.Options(
+		fx.Provide(fx.Annotate(constructor, fx.As(new(mediatr.RequestHandler[TRequest, TResponse])))),
+		fx.Invoke(func(resolver *Resolver, handler mediatr.RequestHandler[TRequest, TResponse]) {
+			resolver.setRequestHandler(typeOf[TRequest](), func() (interface{}, error) {
+				return handler, nil
+			})
+		}),
+	)
+}
+
+// NotificationHandler provides the constructor of a notification handler to the application, and adds
+// the handler to the resolver. Several handlers can be provided for the same notification type.
+func NotificationHandler[TNotification any](constructor interface{}) fx.Option {
+	groupTag := `group:"` + notificationGroup[TNotification]() + `"`
+
+	return fx.Options(
+		fx.Provide(fx.Annotate(
+			constructor,
+			fx.As(new(mediatr.NotificationHandler[TNotification])),
+			fx.ResultTags(groupTag),
+		)),
+		fx.Invoke(fx.Annotate(
+			func(resolver *Resolver, handlers []mediatr.NotificationHandler[TNotification]) {
+				resolver.setNotificationHandlers(typeOf[TNotification](), func() ([]interface{}, error) {
+					return toInterfaces(handlers), nil
+				})
+			},
+			fx.ParamTags(``, groupTag),
+		)),
+	)
+}
@@ -0,0 +1,22 @@
+module github.com/mehdihadeli/go-mediatr/mediatrfx
+
+go 1.24.0
+
+replace github.com/mehdihadeli/go-mediatr => ../
+
+require (
+	github.com/mehdihadeli/go-mediatr v0.0.0-00010101000000-000000000000
+	github.com/stretchr/testify v1.10.0
+	go.uber.org/dig v1.18.0
+	go.uber.org/fx v1.23.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	go.uber.org/multierr v1.10.0 // indirect
+	go.uber.org/zap v1.26.0 // indirect
+	golang.org/x/sys v0.41.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
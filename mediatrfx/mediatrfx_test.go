@@ -0,0 +1,109 @@
+package mediatrfx
+
+import (
+	"context"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/dig"
+	"go.uber.org/fx"
+)
+
+func Test_Dig_Should_Resolve_Provided_Handlers_Lazily(t *testing.T) {
+	container := dig.New()
+	resolver := NewResolver()
+	built := 0
+
+	require.NoError(t, container.Provide(func() *greeter { return &greeter{greeting: "hello"} }))
+	require.NoError(t, ProvideRequestHandler[*greetRequest, *greetResponse](container, resolver, func(g *greeter) *greetHandler {
+		built++
+		return &greetHandler{greeter: g}
+	}))
+	require.NoError(t, ProvideNotificationHandler[*greetedEvent](container, resolver, newGreetedEventHandler))
+	require.NoError(t, ProvideNotificationHandler[*greetedEvent](container, resolver, newGreetedEventHandler))
+
+	mediatr.SetHandlerResolver(resolver)
+	defer mediatr.SetHandlerResolver(nil)
+	assert.Equal(t, 0, built, "handler should not be built before the first request")
+
+	response, err := mediatr.Send[*greetRequest, *greetResponse](context.Background(), &greetRequest{Name: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "hello bob", response.Message)
+	assert.Equal(t, 1, built)
+
+	event := &greetedEvent{}
+	require.NoError(t, mediatr.Publish(context.Background(), event))
+	assert.Equal(t, 2, event.handled, "both notification handlers should be resolved")
+}
+
+func Test_Fx_Module_Should_Set_Resolver_While_Application_Runs(t *testing.T) {
+	app := fx.New(
+		fx.NopLogger,
+		Module,
+		fx.Provide(func() *greeter { return &greeter{greeting: "hi"} }),
+		RequestHandler[*greetRequest, *greetResponse](newGreetHandler),
+		NotificationHandler[*greetedEvent](newGreetedEventHandler),
+		NotificationHandler[*greetedEvent](newGreetedEventHandler),
+	)
+	require.NoError(t, app.Err())
+
+	require.NoError(t, app.Start(context.Background()))
+
+	response, err := mediatr.Send[*greetRequest, *greetResponse](context.Background(), &greetRequest{Name: "bob"})
+	require.NoError(t, err)
+	assert.Equal(t, "hi bob", response.Message)
+
+	event := &greetedEvent{}
+	require.NoError(t, mediatr.Publish(context.Background(), event))
+	assert.Equal(t, 2, event.handled)
+
+	require.NoError(t, app.Stop(context.Background()))
+
+	_, err = mediatr.Send[*greetRequest, *greetResponse](context.Background(), &greetRequest{Name: "bob"})
+	assert.ErrorContains(t, err, "no handler for request", "resolver should be removed when the application stops")
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type greeter struct {
+	greeting string
+}
+
+type greetRequest struct {
+	Name string
+}
+
+type greetResponse struct {
+	Message string
+}
+
+type greetHandler struct {
+	greeter *greeter
+}
+
+func newGreetHandler(g *greeter) *greetHandler {
+	return &greetHandler{greeter: g}
+}
+
+func (h *greetHandler) Handle(ctx context.Context, request *greetRequest) (*greetResponse, error) {
+	return &greetResponse{Message: h.greeter.greeting + " " + request.Name}, nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type greetedEvent struct {
+	handled int
+}
+
+type greetedEventHandler struct {
+}
+
+func newGreetedEventHandler() *greetedEventHandler {
+	return &greetedEventHandler{}
+}
+
+func (h *greetedEventHandler) Handle(ctx context.Context, event *greetedEvent) error {
+	event.handled++
+
+	return nil
+}
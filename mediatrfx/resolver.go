@@ -0,0 +1,97 @@
+// Package mediatrfx integrates the mediator with the go.uber.org/dig container and go.uber.org/fx applications.
+// Handlers are provided to the container with their constructors, and the mediator resolves them
+// through a Resolver set as its mediatr.HandlerResolver.
+package mediatrfx
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// Resolver is a mediatr.HandlerResolver for the handlers provided to a dig container or an fx application.
+type Resolver struct {
+	mutex                sync.RWMutex
+	requestHandlers      map[reflect.Type]func() (interface{}, error)
+	notificationHandlers map[reflect.Type]func() ([]interface{}, error)
+}
+
+// NewResolver creates an empty resolver.
+func NewResolver() *Resolver {
+	return &Resolver{
+		requestHandlers:      map[reflect.Type]func() (interface{}, error){},
+		notificationHandlers: map[reflect.Type]func() ([]interface{}, error){},
+	}
+}
+
+// ResolveRequestHandler implements mediatr.HandlerResolver.
+func (r *Resolver) ResolveRequestHandler(ctx context.Context, requestType reflect.Type) (interface{}, bool, error) {
+	r.mutex.RLock()
+	provider, ok := r.requestHandlers[requestType]
+	r.mutex.RUnlock()
+
+	if !ok {
+		return nil, false, nil
+	}
+
+	handler, err := provider()
+	if err != nil {
+		return nil, false, err
+	}
+
+	return handler, true, nil
+}
+
+// ResolveNotificationHandlers implements mediatr.HandlerResolver.
+func (r *Resolver) ResolveNotificationHandlers(ctx context.Context, notificationType reflect.Type) ([]interface{}, error) {
+	r.mutex.RLock()
+	provider, ok := r.notificationHandlers[notificationType]
+	r.mutex.RUnlock()
+
+	if !ok {
+		return nil, nil
+	}
+
+	return provider()
+}
+
+func (r *Resolver) setRequestHandler(requestType reflect.Type, provider func() (interface{}, error)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.requestHandlers[requestType] = provider
+}
+
+func (r *Resolver) setNotificationHandlers(notificationType reflect.Type, provider func() ([]interface{}, error)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.notificationHandlers[notificationType] = provider
+}
+
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
+
+// notificationGroup returns the name of the value group holding the handlers of a notification type
+func notificationGroup[TNotification any]() string {
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '.' {
+			return r
+		}
+		return '_'
+	}, typeOf[TNotification]().String())
+
+	return "mediatr_notifications_" + name
+}
+
+func toInterfaces[T any](values []T) []interface{} {
+	result := make([]interface{}, 0, len(values))
+	for _, value := range values {
+		result = append(result, value)
+	}
+
+	return result
+}
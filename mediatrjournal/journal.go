@@ -0,0 +1,138 @@
+// Package mediatrjournal records the traffic of the mediator to an append-only JSON Lines journal, and replays it
+// against a new build of the handlers, reporting the responses that differ from the recorded ones. A journal of
+// production traffic can regression-test a rewrite of the handlers.
+//
+// Each line of a journal is an Entry: the request or notification with the name of its type, the response, the
+// error, the Metadata and the duration of a Send or Publish call. The entries are written when the calls complete,
+// so the notifications published by a handler precede the request of the handler.
+package mediatrjournal
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/pkg/errors"
+)
+
+// Kind is the kind of call of a journal entry.
+type Kind string
+
+const (
+	// RequestEntry is the entry of a Send call.
+	RequestEntry Kind = "request"
+	// NotificationEntry is the entry of a Publish call.
+	NotificationEntry Kind = "notification"
+)
+
+// Entry is a line of a journal, Response is empty for notifications and failed requests.
+type Entry struct {
+	Kind     Kind             `json:"kind"`
+	Type     string           `json:"type"`
+	Message  json.RawMessage  `json:"message"`
+	Response json.RawMessage  `json:"response,omitempty"`
+	Error    string           `json:"error,omitempty"`
+	Metadata mediatr.Metadata `json:"metadata"`
+	Duration time.Duration    `json:"duration"`
+}
+
+// Recorder writes the Send and Publish calls of the mediator to a journal. Its behaviors are registered first,
+// so that the requests are recorded as sent, before the other behaviors replace them:
+//
+//	recorder := mediatrjournal.NewRecorder(file)
+//	err := mediatr.RegisterRequestPipelineBehaviors(recorder.RequestBehavior(), &RequestLoggerBehaviour{})
+//	err = mediatr.RegisterNotificationPipelineBehaviors(recorder.NotificationBehavior())
+//
+// A call that can't be recorded is dispatched anyway, the first recording error is returned by Err.
+type Recorder struct {
+	mutex   sync.Mutex
+	encoder *json.Encoder
+	err     error
+}
+
+// NewRecorder creates a recorder appending the entries to w, one JSON object per line.
+func NewRecorder(w io.Writer) *Recorder {
+	return &Recorder{encoder: json.NewEncoder(w)}
+}
+
+// RequestBehavior returns the pipeline behavior recording the Send calls.
+func (r *Recorder) RequestBehavior() mediatr.PipelineBehavior {
+	return &requestRecorder{recorder: r}
+}
+
+// NotificationBehavior returns the notification pipeline behavior recording the Publish calls.
+func (r *Recorder) NotificationBehavior() mediatr.NotificationPipelineBehavior {
+	return &notificationRecorder{recorder: r}
+}
+
+// Err returns the first error encountered while recording, if any.
+func (r *Recorder) Err() error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	return r.err
+}
+
+// record writes the entry of a completed call
+func (r *Recorder) record(ctx context.Context, kind Kind, message interface{}, response interface{}, err error, start time.Time) {
+	entry := &Entry{Kind: kind, Type: TypeName(reflect.TypeOf(message)), Duration: time.Since(start)}
+	entry.Metadata, _ = mediatr.MetadataFromContext(ctx)
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	var marshalErr error
+	entry.Message, marshalErr = json.Marshal(message)
+	if marshalErr == nil && kind == RequestEntry && err == nil {
+		entry.Response, marshalErr = json.Marshal(response)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if marshalErr != nil {
+		r.fail(errors.Wrapf(marshalErr, "recording %s", entry.Type))
+		return
+	}
+	r.fail(r.encoder.Encode(entry))
+}
+
+func (r *Recorder) fail(err error) {
+	if r.err == nil {
+		r.err = err
+	}
+}
+
+// requestRecorder is the pipeline behavior of a recorder
+type requestRecorder struct {
+	recorder *Recorder
+}
+
+func (b *requestRecorder) Name() string {
+	return "mediatrjournal.recorder"
+}
+
+func (b *requestRecorder) Handle(ctx context.Context, request interface{}, next mediatr.RequestHandlerFunc) (interface{}, error) {
+	start := time.Now()
+	response, err := next(ctx)
+	b.recorder.record(ctx, RequestEntry, request, response, err, start)
+
+	return response, err
+}
+
+// notificationRecorder is the notification pipeline behavior of a recorder
+type notificationRecorder struct {
+	recorder *Recorder
+}
+
+func (b *notificationRecorder) Handle(ctx context.Context, notification interface{}, next mediatr.NotificationHandlerFunc) error {
+	start := time.Now()
+	err := next(ctx)
+	b.recorder.record(ctx, NotificationEntry, notification, nil, err, start)
+
+	return err
+}
@@ -0,0 +1,125 @@
+package mediatrjournal
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/mehdihadeli/go-mediatr/mediatrtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Recorder_Should_Write_Calls_As_JSON_Lines(t *testing.T) {
+	mediatrtest.New(t)
+	var journal bytes.Buffer
+	recorder := newTestRecorder(t, &journal)
+	require.NoError(t, mediatr.RegisterRequestHandler[*createProduct, *createProductResponse](&createProductHandler{}))
+
+	_, err := mediatr.Send[*createProduct, *createProductResponse](context.Background(), &createProduct{Name: "pizza", Price: 12}, mediatr.WithUserID("bob"))
+
+	require.NoError(t, err)
+	require.NoError(t, recorder.Err())
+	entries := readEntries(t, journal.String())
+	require.Len(t, entries, 2)
+
+	notification, request := entries[0], entries[1]
+	assert.Equal(t, NotificationEntry, notification.Kind)
+	assert.Equal(t, "*github.com/mehdihadeli/go-mediatr/mediatrjournal.productCreated", notification.Type)
+	assert.JSONEq(t, `{"name":"pizza","price":12}`, string(notification.Message))
+	assert.Equal(t, request.Metadata.MessageID, notification.Metadata.CausationID)
+
+	assert.Equal(t, RequestEntry, request.Kind)
+	assert.Equal(t, "*github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct", request.Type)
+	assert.JSONEq(t, `{"name":"pizza","price":12}`, string(request.Message))
+	assert.JSONEq(t, `{"productId":"pizza-1","name":"pizza"}`, string(request.Response))
+	assert.Equal(t, "bob", request.Metadata.UserID)
+	assert.Positive(t, request.Duration)
+}
+
+func Test_Recorder_Should_Record_Errors(t *testing.T) {
+	mediatrtest.New(t)
+	var journal bytes.Buffer
+	newTestRecorder(t, &journal)
+	mediatrtest.Stub[*createProduct, *createProductResponse](t, nil, errors.New("out of stock"))
+
+	_, err := mediatr.Send[*createProduct, *createProductResponse](context.Background(), &createProduct{Name: "pizza"})
+
+	require.Error(t, err)
+	entries := readEntries(t, journal.String())
+	require.Len(t, entries, 1)
+	assert.Equal(t, "out of stock", entries[0].Error)
+	assert.Empty(t, entries[0].Response)
+}
+
+func Test_Recorder_Should_Keep_Dispatching_If_A_Message_Cant_Be_Encoded(t *testing.T) {
+	mediatrtest.New(t)
+	var journal bytes.Buffer
+	recorder := newTestRecorder(t, &journal)
+
+	err := mediatr.Publish(context.Background(), &unencodable{Callback: func() {}})
+
+	assert.NoError(t, err)
+	assert.ErrorContains(t, recorder.Err(), "recording *github.com/mehdihadeli/go-mediatr/mediatrjournal.unencodable")
+	assert.Empty(t, journal.String())
+}
+
+func newTestRecorder(t *testing.T, journal *bytes.Buffer) *Recorder {
+	recorder := NewRecorder(journal)
+	require.NoError(t, mediatr.RegisterRequestPipelineBehaviors(recorder.RequestBehavior()))
+	require.NoError(t, mediatr.RegisterNotificationPipelineBehaviors(recorder.NotificationBehavior()))
+
+	return recorder
+}
+
+func readEntries(t *testing.T, journal string) []*Entry {
+	var entries []*Entry
+	for _, line := range strings.Split(strings.TrimSpace(journal), "\n") {
+		entry := &Entry{}
+		require.NoError(t, json.Unmarshal([]byte(line), entry))
+		entries = append(entries, entry)
+	}
+
+	return entries
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type createProduct struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+type createProductResponse struct {
+	ProductID string `json:"productId"`
+	Name      string `json:"name"`
+}
+
+type productCreated struct {
+	Name  string  `json:"name"`
+	Price float64 `json:"price"`
+}
+
+type unencodable struct {
+	Callback func()
+}
+
+// createProductHandler publishes a productCreated event, and suffixes the ids with a version
+type createProductHandler struct {
+	version string
+}
+
+func (h *createProductHandler) Handle(ctx context.Context, command *createProduct) (*createProductResponse, error) {
+	if err := mediatr.Publish(ctx, &productCreated{Name: command.Name, Price: command.Price}); err != nil {
+		return nil, err
+	}
+	version := h.version
+	if version == "" {
+		version = "1"
+	}
+
+	return &createProductResponse{ProductID: command.Name + "-" + version, Name: command.Name}, nil
+}
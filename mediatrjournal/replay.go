@@ -0,0 +1,302 @@
+package mediatrjournal
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/pkg/errors"
+)
+
+// Replayer dispatches the calls of a journal to the handlers registered in the mediator, and compares their
+// responses and errors with the recorded ones.
+//
+// Example:
+//
+//	types := mediatrjournal.NewTypes()
+//	_ = mediatrjournal.RegisterRequest[*CreateProductCommand, *CreateProductCommandResponse](types)
+//
+//	replayer := &mediatrjournal.Replayer{Types: types, Ignore: []string{"productId"}}
+//	report, err := replayer.Replay(ctx, file)
+//	if err == nil && !report.OK() {
+//	    log.Fatal(report)
+//	}
+type Replayer struct {
+	// Types are the message types of the replayed entries, the entries of other types are skipped.
+	Types *Types
+	// Ignore are the names of the JSON fields left out of the response comparisons, at any depth,
+	// e.g. generated ids and timestamps.
+	Ignore []string
+}
+
+// Report is the result of a replay.
+type Report struct {
+	// Replayed is the number of replayed entries.
+	Replayed int
+	// Mismatches are the replayed entries whose response or error differ from the recorded ones.
+	Mismatches []Mismatch
+	// Skipped are the types of the entries skipped because they are not registered in the Types of the replayer.
+	Skipped []string
+}
+
+// Mismatch is a replayed entry whose response or error differ from the recorded ones.
+type Mismatch struct {
+	// Line is the line of the entry in the journal.
+	Line        int
+	Entry       *Entry
+	Differences []string
+}
+
+// OK reports whether every replayed entry matched its recording.
+func (r *Report) OK() bool {
+	return len(r.Mismatches) == 0
+}
+
+func (r *Report) String() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "replayed %d entries, %d mismatches", r.Replayed, len(r.Mismatches))
+	if len(r.Skipped) > 0 {
+		fmt.Fprintf(&builder, ", skipped unregistered types %s", strings.Join(r.Skipped, ", "))
+	}
+	for _, mismatch := range r.Mismatches {
+		fmt.Fprintf(
+			&builder,
+			"\nline %d: %s %s (message %s):\n  %s",
+			mismatch.Line,
+			mismatch.Entry.Kind,
+			mismatch.Entry.Type,
+			mismatch.Entry.Metadata.MessageID,
+			strings.Join(mismatch.Differences, "\n  "),
+		)
+	}
+
+	return builder.String()
+}
+
+// journalEntry is an entry read from a journal, with its line
+type journalEntry struct {
+	line  int
+	entry *Entry
+}
+
+// Replay reads the journal and dispatches its calls in the order they were made. Only the calls made outside of
+// a handler are dispatched, the calls made by the handlers being made again by the new handlers. Each call is
+// dispatched with the recorded correlation id and user id.
+// Returns error if the journal can't be read, the mismatches are listed in the report.
+func (r *Replayer) Replay(ctx context.Context, journal io.Reader) (*Report, error) {
+	entries, err := readJournal(journal)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &Report{}
+	skipped := map[string]struct{}{}
+	for _, entry := range rootEntries(entries) {
+		messageType, ok := r.Types.lookup(entry.entry)
+		if !ok {
+			skipped[entry.entry.Type] = struct{}{}
+			continue
+		}
+
+		differences, err := r.replay(ctx, messageType, entry.entry)
+		if err != nil {
+			return nil, errors.Wrapf(err, "line %d", entry.line)
+		}
+		report.Replayed++
+		if len(differences) > 0 {
+			report.Mismatches = append(report.Mismatches, Mismatch{Line: entry.line, Entry: entry.entry, Differences: differences})
+		}
+	}
+	report.Skipped = slices.Sorted(maps.Keys(skipped))
+
+	return report, nil
+}
+
+// replay dispatches an entry and returns the differences with its recording
+func (r *Replayer) replay(ctx context.Context, messageType *messageType, entry *Entry) ([]string, error) {
+	message, err := messageType.decode(entry.Message)
+	if err != nil {
+		return nil, err
+	}
+
+	response, dispatchErr := messageType.dispatch(
+		ctx,
+		message,
+		mediatr.WithCorrelationID(entry.Metadata.CorrelationID),
+		mediatr.WithUserID(entry.Metadata.UserID),
+	)
+
+	var differences []string
+	switch {
+	case dispatchErr != nil && !sameError(dispatchErr, entry.Error):
+		differences = append(differences, fmt.Sprintf("error: expected %s, got %q", formatError(entry.Error), dispatchErr.Error()))
+	case dispatchErr == nil && entry.Error != "":
+		differences = append(differences, fmt.Sprintf("error: expected %q, got none", entry.Error))
+	}
+	if dispatchErr != nil || entry.Kind != RequestEntry || entry.Error != "" {
+		return differences, nil
+	}
+
+	replayed, err := json.Marshal(response)
+	if err != nil {
+		return nil, errors.Wrapf(err, "encoding the response of %s", entry.Type)
+	}
+	responseDifferences, err := diffJSON(entry.Response, replayed, r.Ignore)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(differences, responseDifferences...), nil
+}
+
+// sameError reports whether err is the recorded error, which is recorded before the pipeline error wrapping of Send
+func sameError(err error, recorded string) bool {
+	return recorded != "" && (err.Error() == recorded || strings.HasSuffix(err.Error(), ": "+recorded))
+}
+
+func formatError(err string) string {
+	if err == "" {
+		return "none"
+	}
+
+	return strconv.Quote(err)
+}
+
+// readJournal reads the entries of a journal
+func readJournal(journal io.Reader) ([]journalEntry, error) {
+	decoder := json.NewDecoder(journal)
+	var entries []journalEntry
+	for line := 1; ; line++ {
+		entry := &Entry{}
+		err := decoder.Decode(entry)
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "reading journal line %d", line)
+		}
+		entries = append(entries, journalEntry{line: line, entry: entry})
+	}
+}
+
+// rootEntries returns the entries of the calls made outside of a handler, whose cause isn't in the journal,
+// in the order the calls started
+func rootEntries(entries []journalEntry) []journalEntry {
+	messageIDs := map[string]struct{}{}
+	for _, entry := range entries {
+		messageIDs[entry.entry.Metadata.MessageID] = struct{}{}
+	}
+
+	var roots []journalEntry
+	for _, entry := range entries {
+		causationID := entry.entry.Metadata.CausationID
+		if _, caused := messageIDs[causationID]; causationID == "" || !caused {
+			roots = append(roots, entry)
+		}
+	}
+	sort.SliceStable(roots, func(i, j int) bool {
+		return roots[i].entry.Metadata.Timestamp.Before(roots[j].entry.Metadata.Timestamp)
+	})
+
+	return roots
+}
+
+// diffJSON returns the differences between two JSON documents, one "path: expected x, got y" line per difference
+func diffJSON(expected []byte, actual []byte, ignore []string) ([]string, error) {
+	var expectedValue, actualValue interface{}
+	if len(expected) > 0 {
+		if err := json.Unmarshal(expected, &expectedValue); err != nil {
+			return nil, errors.Wrap(err, "decoding the recorded response")
+		}
+	}
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		return nil, errors.Wrap(err, "decoding the replayed response")
+	}
+
+	var differences []string
+	compareJSON("", expectedValue, actualValue, ignore, &differences)
+
+	return differences, nil
+}
+
+func compareJSON(path string, expected interface{}, actual interface{}, ignore []string, differences *[]string) {
+	switch expectedValue := expected.(type) {
+	case map[string]interface{}:
+		actualValue, ok := actual.(map[string]interface{})
+		if !ok {
+			addDifference(differences, path, "expected %s, got %s", formatJSON(expected), formatJSON(actual))
+			return
+		}
+		keys := slices.Collect(maps.Keys(expectedValue))
+		for key := range actualValue {
+			if _, ok := expectedValue[key]; !ok {
+				keys = append(keys, key)
+			}
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if slices.Contains(ignore, key) {
+				continue
+			}
+			keyPath := key
+			if path != "" {
+				keyPath = path + "." + key
+			}
+			expectedField, expectedOK := expectedValue[key]
+			actualField, actualOK := actualValue[key]
+			switch {
+			case !actualOK:
+				addDifference(differences, keyPath, "missing %s", formatJSON(expectedField))
+			case !expectedOK:
+				addDifference(differences, keyPath, "unexpected %s", formatJSON(actualField))
+			default:
+				compareJSON(keyPath, expectedField, actualField, ignore, differences)
+			}
+		}
+	case []interface{}:
+		actualValue, ok := actual.([]interface{})
+		if !ok {
+			addDifference(differences, path, "expected %s, got %s", formatJSON(expected), formatJSON(actual))
+			return
+		}
+		for i := 0; i < max(len(expectedValue), len(actualValue)); i++ {
+			elementPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= len(actualValue):
+				addDifference(differences, elementPath, "missing %s", formatJSON(expectedValue[i]))
+			case i >= len(expectedValue):
+				addDifference(differences, elementPath, "unexpected %s", formatJSON(actualValue[i]))
+			default:
+				compareJSON(elementPath, expectedValue[i], actualValue[i], ignore, differences)
+			}
+		}
+	default:
+		// the scalars decoded from JSON are comparable, an unequal type is unequal
+		if expected != actual {
+			addDifference(differences, path, "expected %s, got %s", formatJSON(expected), formatJSON(actual))
+		}
+	}
+}
+
+func addDifference(differences *[]string, path string, message string, args ...interface{}) {
+	if path == "" {
+		path = "response"
+	}
+	*differences = append(*differences, path+": "+fmt.Sprintf(message, args...))
+}
+
+func formatJSON(value interface{}) string {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprint(value)
+	}
+
+	return string(encoded)
+}
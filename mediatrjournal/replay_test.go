@@ -0,0 +1,128 @@
+package mediatrjournal
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/mehdihadeli/go-mediatr/mediatrtest"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Replay_Should_Dispatch_Calls_Made_Outside_Of_Handlers(t *testing.T) {
+	journal := recordJournal(t, &createProductHandler{})
+	fake := mediatrtest.New(t)
+	require.NoError(t, mediatr.RegisterRequestHandler[*createProduct, *createProductResponse](&createProductHandler{}))
+
+	report, err := (&Replayer{Types: testTypes(t)}).Replay(context.Background(), strings.NewReader(journal))
+
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.String())
+	assert.Equal(t, 2, report.Replayed)
+	assert.Len(t, mediatrtest.Published[*productCreated](t), 2, "the notifications published by the handler should not be replayed")
+	assert.Equal(t, "pizza", fake.Calls()[0].Message.(*createProduct).Name, "the calls should be replayed in the order they were made")
+}
+
+func Test_Replay_Should_Report_Response_Differences(t *testing.T) {
+	journal := recordJournal(t, &createProductHandler{})
+	mediatrtest.New(t)
+	require.NoError(t, mediatr.RegisterRequestHandler[*createProduct, *createProductResponse](&createProductHandler{version: "2"}))
+
+	report, err := (&Replayer{Types: testTypes(t)}).Replay(context.Background(), strings.NewReader(journal))
+
+	require.NoError(t, err)
+	assert.False(t, report.OK())
+	require.Len(t, report.Mismatches, 2)
+	assert.Equal(t, 2, report.Mismatches[0].Line)
+	assert.Equal(t, []string{`productId: expected "pizza-1", got "pizza-2"`}, report.Mismatches[0].Differences)
+	assert.Contains(t, report.String(), "replayed 2 entries, 2 mismatches\nline 2: request *github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct")
+}
+
+func Test_Replay_Should_Not_Compare_Ignored_Fields(t *testing.T) {
+	journal := recordJournal(t, &createProductHandler{})
+	mediatrtest.New(t)
+	require.NoError(t, mediatr.RegisterRequestHandler[*createProduct, *createProductResponse](&createProductHandler{version: "2"}))
+
+	report, err := (&Replayer{Types: testTypes(t), Ignore: []string{"productId"}}).Replay(context.Background(), strings.NewReader(journal))
+
+	require.NoError(t, err)
+	assert.True(t, report.OK(), report.String())
+}
+
+func Test_Replay_Should_Report_Error_Differences(t *testing.T) {
+	journal := recordJournal(t, &createProductHandler{})
+	mediatrtest.New(t)
+	mediatrtest.Stub[*createProduct, *createProductResponse](t, nil, errors.New("out of stock"))
+
+	report, err := (&Replayer{Types: testTypes(t)}).Replay(context.Background(), strings.NewReader(journal))
+
+	require.NoError(t, err)
+	require.Len(t, report.Mismatches, 2)
+	assert.Equal(t, []string{`error: expected none, got "handler error: out of stock"`}, report.Mismatches[0].Differences)
+}
+
+func Test_Replay_Should_Match_Recorded_Error_Wrapped_By_Send(t *testing.T) {
+	var journal bytes.Buffer
+	t.Run("record", func(t *testing.T) {
+		mediatrtest.New(t)
+		newTestRecorder(t, &journal)
+		mediatrtest.Stub[*createProduct, *createProductResponse](t, nil, errors.New("out of stock"))
+		_, err := mediatr.Send[*createProduct, *createProductResponse](context.Background(), &createProduct{Name: "pizza"})
+		require.Error(t, err)
+	})
+	mediatrtest.New(t)
+	mediatrtest.Stub[*createProduct, *createProductResponse](t, nil, errors.New("out of stock"))
+
+	report, err := (&Replayer{Types: testTypes(t)}).Replay(context.Background(), &journal)
+
+	require.NoError(t, err)
+	assert.Equal(t, 1, report.Replayed)
+	assert.True(t, report.OK(), report.String())
+}
+
+func Test_Replay_Should_Skip_Unregistered_Types(t *testing.T) {
+	journal := recordJournal(t, &createProductHandler{})
+	mediatrtest.New(t)
+
+	report, err := (&Replayer{Types: NewTypes()}).Replay(context.Background(), strings.NewReader(journal))
+
+	require.NoError(t, err)
+	assert.Equal(t, 0, report.Replayed)
+	assert.Equal(t, []string{"*github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct"}, report.Skipped)
+}
+
+func Test_Replay_Should_Return_Error_For_Invalid_Journal(t *testing.T) {
+	_, err := (&Replayer{Types: NewTypes()}).Replay(context.Background(), strings.NewReader("{\"kind\":\"request\"}\n{"))
+
+	assert.ErrorContains(t, err, "reading journal line 2")
+}
+
+// recordJournal records the creation of two products with handler, in an isolated mediator
+func recordJournal(t *testing.T, handler *createProductHandler) string {
+	var journal bytes.Buffer
+	t.Run("record", func(t *testing.T) {
+		mediatrtest.New(t)
+		recorder := newTestRecorder(t, &journal)
+		require.NoError(t, mediatr.RegisterRequestHandler[*createProduct, *createProductResponse](handler))
+
+		for _, name := range []string{"pizza", "pasta"} {
+			_, err := mediatr.Send[*createProduct, *createProductResponse](context.Background(), &createProduct{Name: name, Price: 12})
+			require.NoError(t, err)
+		}
+		require.NoError(t, recorder.Err())
+	})
+
+	return journal.String()
+}
+
+func testTypes(t *testing.T) *Types {
+	types := NewTypes()
+	require.NoError(t, RegisterRequest[*createProduct, *createProductResponse](types))
+	require.NoError(t, RegisterNotification[*productCreated](types))
+
+	return types
+}
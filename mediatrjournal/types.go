@@ -0,0 +1,108 @@
+package mediatrjournal
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"sync"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/pkg/errors"
+)
+
+// Types is the registry of the message types a journal is replayed with. The entries of a journal name the type
+// of their message, the registry decodes the message of a name and dispatches it with its typed Send or Publish.
+type Types struct {
+	mutex  sync.RWMutex
+	byName map[string]*messageType
+}
+
+// messageType is a registered message type, dispatch sends or publishes a message of the type
+type messageType struct {
+	kind     Kind
+	typ      reflect.Type
+	dispatch func(ctx context.Context, message interface{}, opts ...mediatr.Option) (interface{}, error)
+}
+
+// NewTypes creates an empty registry of message types.
+func NewTypes() *Types {
+	return &Types{byName: map[string]*messageType{}}
+}
+
+// RegisterRequest registers the request type TRequest, replayed with Send[TRequest, TResponse].
+// Returns error if the type is already registered.
+//
+// Example:
+//
+//	types := mediatrjournal.NewTypes()
+//	err := mediatrjournal.RegisterRequest[*CreateProductCommand, *CreateProductCommandResponse](types)
+func RegisterRequest[TRequest any, TResponse any](types *Types) error {
+	return types.add(&messageType{
+		kind: RequestEntry,
+		typ:  reflect.TypeOf((*TRequest)(nil)).Elem(),
+		dispatch: func(ctx context.Context, message interface{}, opts ...mediatr.Option) (interface{}, error) {
+			return mediatr.Send[TRequest, TResponse](ctx, message.(TRequest), opts...)
+		},
+	})
+}
+
+// RegisterNotification registers the notification type TNotification, replayed with Publish[TNotification].
+// Returns error if the type is already registered.
+func RegisterNotification[TNotification any](types *Types) error {
+	return types.add(&messageType{
+		kind: NotificationEntry,
+		typ:  reflect.TypeOf((*TNotification)(nil)).Elem(),
+		dispatch: func(ctx context.Context, message interface{}, opts ...mediatr.Option) (interface{}, error) {
+			return nil, mediatr.Publish(ctx, message.(TNotification), opts...)
+		},
+	})
+}
+
+func (t *Types) add(messageType *messageType) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	name := TypeName(messageType.typ)
+	if _, exists := t.byName[name]; exists {
+		return errors.Errorf("type %s already registered", name)
+	}
+	t.byName[name] = messageType
+
+	return nil
+}
+
+// lookup returns the registered type of an entry, or false if the type isn't registered for the kind of the entry
+func (t *Types) lookup(entry *Entry) (*messageType, bool) {
+	t.mutex.RLock()
+	defer t.mutex.RUnlock()
+
+	messageType, ok := t.byName[entry.Type]
+	if !ok || messageType.kind != entry.Kind {
+		return nil, false
+	}
+
+	return messageType, true
+}
+
+// decode decodes the JSON message of an entry into a value of the type
+func (m *messageType) decode(message json.RawMessage) (interface{}, error) {
+	value := reflect.New(m.typ)
+	if err := json.Unmarshal(message, value.Interface()); err != nil {
+		return nil, errors.Wrapf(err, "decoding %s", TypeName(m.typ))
+	}
+
+	return value.Elem().Interface(), nil
+}
+
+// TypeName returns the name of a message type in the journal entries: the import path of its package and its
+// name, prefixed by * for a pointer type, e.g. "*example.com/shop/products/commands.CreateProductCommand".
+func TypeName(t reflect.Type) string {
+	if t.Kind() == reflect.Pointer {
+		return "*" + TypeName(t.Elem())
+	}
+	if t.Name() == "" || t.PkgPath() == "" {
+		return t.String()
+	}
+
+	return t.PkgPath() + "." + t.Name()
+}
@@ -0,0 +1,39 @@
+package mediatrjournal
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_TypeName_Should_Qualify_Named_Types_With_Their_Package(t *testing.T) {
+	assert.Equal(t, "*github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct", TypeName(reflect.TypeOf(&createProduct{})))
+	assert.Equal(t, "github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct", TypeName(reflect.TypeOf(createProduct{})))
+	assert.Equal(t, "string", TypeName(reflect.TypeOf("")))
+	assert.Equal(t, "[]string", TypeName(reflect.TypeOf([]string{})))
+}
+
+func Test_RegisterRequest_Should_Return_Error_If_Type_Already_Registered(t *testing.T) {
+	types := NewTypes()
+	require.NoError(t, RegisterRequest[*createProduct, *createProductResponse](types))
+
+	err := RegisterNotification[*createProduct](types)
+
+	assert.EqualError(t, err, "type *github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct already registered")
+}
+
+func Test_Types_Should_Decode_Message_Of_Registered_Type(t *testing.T) {
+	types := NewTypes()
+	require.NoError(t, RegisterRequest[*createProduct, *createProductResponse](types))
+
+	messageType, ok := types.lookup(&Entry{Kind: RequestEntry, Type: "*github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct"})
+	require.True(t, ok)
+	message, err := messageType.decode([]byte(`{"name":"pizza","price":12}`))
+
+	require.NoError(t, err)
+	assert.Equal(t, &createProduct{Name: "pizza", Price: 12}, message)
+	_, ok = types.lookup(&Entry{Kind: NotificationEntry, Type: "*github.com/mehdihadeli/go-mediatr/mediatrjournal.createProduct"})
+	assert.False(t, ok, "a request type should not replay notification entries")
+}
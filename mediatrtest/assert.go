@@ -0,0 +1,92 @@
+package mediatrtest
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// AssertSent checks that a request of type T matching predicate was sent, a nil predicate matches any request of type T.
+// Returns whether the assertion succeeded, the failure lists the recorded Send calls.
+//
+// Example:
+//
+//	mediatrtest.AssertSent(t, func(command *ReserveStockCommand) bool {
+//	    return command.ProductID == productID
+//	})
+func AssertSent[T any](t testing.TB, predicate func(T) bool) bool {
+	t.Helper()
+
+	return assertCall(t, SendCall, predicate, true)
+}
+
+// AssertPublished checks that a notification of type T matching predicate was published, a nil predicate matches
+// any notification of type T. Returns whether the assertion succeeded, the failure lists the recorded Publish calls.
+//
+// Example:
+//
+//	mediatrtest.AssertPublished(t, func(event *ProductCreatedEvent) bool {
+//	    return event.Name == "pizza"
+//	})
+func AssertPublished[T any](t testing.TB, predicate func(T) bool) bool {
+	t.Helper()
+
+	return assertCall(t, PublishCall, predicate, true)
+}
+
+// AssertNotPublished checks that no notification of type T matching predicate was published, a nil predicate
+// matches any notification of type T.
+func AssertNotPublished[T any](t testing.TB, predicate func(T) bool) bool {
+	t.Helper()
+
+	return assertCall(t, PublishCall, predicate, false)
+}
+
+func assertCall[T any](t testing.TB, kind CallKind, predicate func(T) bool, expected bool) bool {
+	t.Helper()
+
+	calls := fakeOf(t).Calls()
+	found := false
+	for _, call := range calls {
+		message, ok := call.Message.(T)
+		if ok && call.Kind == kind && (predicate == nil || predicate(message)) {
+			found = true
+			break
+		}
+	}
+	if found == expected {
+		return true
+	}
+
+	messageType := reflect.TypeOf((*T)(nil)).Elem()
+	if expected {
+		t.Errorf("mediatrtest: no %s call of %s matching the predicate\n%s", kind, messageType, describeCalls(calls, kind))
+	} else {
+		t.Errorf("mediatrtest: unexpected %s call of %s matching the predicate\n%s", kind, messageType, describeCalls(calls, kind))
+	}
+
+	return false
+}
+
+// describeCalls lists the recorded calls of a kind, for the assertion failures
+func describeCalls(calls []Call, kind CallKind) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "recorded %s calls:", kind)
+	count := 0
+	for _, call := range calls {
+		if call.Kind != kind {
+			continue
+		}
+		count++
+		fmt.Fprintf(&builder, "\n  %d. %T %+v", count, call.Message, call.Message)
+		if call.Err != nil {
+			fmt.Fprintf(&builder, " (error: %v)", call.Err)
+		}
+	}
+	if count == 0 {
+		builder.WriteString(" none")
+	}
+
+	return builder.String()
+}
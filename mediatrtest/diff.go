@@ -0,0 +1,207 @@
+package mediatrtest
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+)
+
+// differ compares two values field by field, and records a readable line for each difference
+type differ struct {
+	// ignore are the names of the struct fields left out of the comparison, at any depth
+	ignore  []string
+	visited map[[2]uintptr]bool
+	lines   []string
+}
+
+// diff returns the differences between expected and actual, one "path: expected x, got y" line per difference
+func diff(expected interface{}, actual interface{}, ignore []string) []string {
+	d := &differ{ignore: ignore, visited: map[[2]uintptr]bool{}}
+	d.compare("", reflect.ValueOf(expected), reflect.ValueOf(actual))
+
+	return d.lines
+}
+
+func (d *differ) compare(path string, expected reflect.Value, actual reflect.Value) {
+	if !expected.IsValid() || !actual.IsValid() {
+		if expected.IsValid() != actual.IsValid() {
+			d.report(path, expected, actual)
+		}
+		return
+	}
+	if expected.Type() != actual.Type() {
+		d.addf(path, "expected %s, got %s", formatTyped(expected), formatTyped(actual))
+		return
+	}
+	if equal, ok := callEqual(expected, actual); ok {
+		if !equal {
+			d.report(path, expected, actual)
+		}
+		return
+	}
+
+	switch expected.Kind() {
+	case reflect.Pointer:
+		if expected.IsNil() || actual.IsNil() {
+			if expected.IsNil() != actual.IsNil() {
+				d.report(path, expected, actual)
+			}
+			return
+		}
+		visit := [2]uintptr{expected.Pointer(), actual.Pointer()}
+		if visit[0] == visit[1] || d.visited[visit] {
+			return
+		}
+		d.visited[visit] = true
+		d.compare(path, expected.Elem(), actual.Elem())
+	case reflect.Interface:
+		if expected.IsNil() || actual.IsNil() {
+			if expected.IsNil() != actual.IsNil() {
+				d.report(path, expected, actual)
+			}
+			return
+		}
+		d.compare(path, expected.Elem(), actual.Elem())
+	case reflect.Struct:
+		for i := 0; i < expected.NumField(); i++ {
+			name := expected.Type().Field(i).Name
+			if slices.Contains(d.ignore, name) {
+				continue
+			}
+			d.compare(joinPath(path, name), expected.Field(i), actual.Field(i))
+		}
+	case reflect.Slice, reflect.Array:
+		for i := 0; i < max(expected.Len(), actual.Len()); i++ {
+			elementPath := path + "[" + strconv.Itoa(i) + "]"
+			switch {
+			case i >= actual.Len():
+				d.addf(elementPath, "missing %s", format(expected.Index(i)))
+			case i >= expected.Len():
+				d.addf(elementPath, "unexpected %s", format(actual.Index(i)))
+			default:
+				d.compare(elementPath, expected.Index(i), actual.Index(i))
+			}
+		}
+	case reflect.Map:
+		for _, key := range mapKeys(expected, actual) {
+			keyPath := path + "[" + format(key) + "]"
+			expectedValue, actualValue := expected.MapIndex(key), actual.MapIndex(key)
+			switch {
+			case !actualValue.IsValid():
+				d.addf(keyPath, "missing %s", format(expectedValue))
+			case !expectedValue.IsValid():
+				d.addf(keyPath, "unexpected %s", format(actualValue))
+			default:
+				d.compare(keyPath, expectedValue, actualValue)
+			}
+		}
+	case reflect.Func, reflect.Chan, reflect.UnsafePointer:
+		if expected.Pointer() != actual.Pointer() {
+			d.report(path, expected, actual)
+		}
+	default:
+		if !equalScalars(expected, actual) {
+			d.report(path, expected, actual)
+		}
+	}
+}
+
+func (d *differ) report(path string, expected reflect.Value, actual reflect.Value) {
+	d.addf(path, "expected %s, got %s", format(expected), format(actual))
+}
+
+func (d *differ) addf(path string, message string, args ...interface{}) {
+	if path == "" {
+		path = "value"
+	}
+	d.lines = append(d.lines, path+": "+fmt.Sprintf(message, args...))
+}
+
+// callEqual compares the values with their Equal method, e.g. for time.Time, whose fields can't be compared
+func callEqual(expected reflect.Value, actual reflect.Value) (equal bool, ok bool) {
+	if !expected.CanInterface() || !actual.CanInterface() {
+		return false, false
+	}
+	if expected.Kind() == reflect.Pointer && (expected.IsNil() || actual.IsNil()) {
+		return false, false
+	}
+	method := expected.MethodByName("Equal")
+	if !method.IsValid() {
+		return false, false
+	}
+	methodType := method.Type()
+	if methodType.NumIn() != 1 || methodType.In(0) != expected.Type() ||
+		methodType.NumOut() != 1 || methodType.Out(0).Kind() != reflect.Bool {
+		return false, false
+	}
+
+	return method.Call([]reflect.Value{actual})[0].Bool(), true
+}
+
+// equalScalars compares the values of a basic kind, which may be read from unexported fields
+func equalScalars(expected reflect.Value, actual reflect.Value) bool {
+	switch expected.Kind() {
+	case reflect.Bool:
+		return expected.Bool() == actual.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return expected.Int() == actual.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return expected.Uint() == actual.Uint()
+	case reflect.Float32, reflect.Float64:
+		return expected.Float() == actual.Float()
+	case reflect.Complex64, reflect.Complex128:
+		return expected.Complex() == actual.Complex()
+	case reflect.String:
+		return expected.String() == actual.String()
+	default:
+		return false
+	}
+}
+
+// mapKeys returns the keys of both maps, sorted by their formatted value
+func mapKeys(expected reflect.Value, actual reflect.Value) []reflect.Value {
+	keys := map[string]reflect.Value{}
+	for _, key := range append(expected.MapKeys(), actual.MapKeys()...) {
+		keys[format(key)] = key
+	}
+
+	sorted := make([]reflect.Value, 0, len(keys))
+	for _, name := range slices.Sorted(maps.Keys(keys)) {
+		sorted = append(sorted, keys[name])
+	}
+
+	return sorted
+}
+
+func joinPath(path string, name string) string {
+	if path == "" {
+		return name
+	}
+
+	return path + "." + name
+}
+
+// format renders a value for a difference, quoting the strings
+func format(value reflect.Value) string {
+	switch {
+	case !value.IsValid():
+		return "nil"
+	case value.Kind() == reflect.String:
+		return strconv.Quote(value.String())
+	case (value.Kind() == reflect.Pointer || value.Kind() == reflect.Interface) && value.IsNil():
+		return "nil"
+	default:
+		return fmt.Sprintf("%+v", value)
+	}
+}
+
+// formatTyped renders a value with its type, for the values of different types
+func formatTyped(value reflect.Value) string {
+	if !value.IsValid() {
+		return "nil"
+	}
+
+	return value.Type().String() + " " + format(value)
+}
@@ -0,0 +1,63 @@
+package mediatrtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Diff_Should_Report_Nested_Differences_By_Path(t *testing.T) {
+	type line struct {
+		Product  string
+		Quantity int
+	}
+	type order struct {
+		Lines  []line
+		Tags   map[string]string
+		note   string
+		Placed time.Time
+	}
+	placed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	expected := &order{
+		Lines:  []line{{Product: "pizza", Quantity: 1}, {Product: "soda", Quantity: 2}},
+		Tags:   map[string]string{"channel": "web", "promo": "spring"},
+		note:   "ring twice",
+		Placed: placed,
+	}
+	actual := &order{
+		Lines:  []line{{Product: "pizza", Quantity: 3}},
+		Tags:   map[string]string{"channel": "app", "table": "4"},
+		note:   "ring once",
+		Placed: placed.In(time.FixedZone("CET", 3600)),
+	}
+
+	differences := diff(expected, actual, nil)
+
+	assert.Equal(t, []string{
+		"Lines[0].Quantity: expected 1, got 3",
+		"Lines[1]: missing {Product:soda Quantity:2}",
+		`Tags["channel"]: expected "web", got "app"`,
+		`Tags["promo"]: missing "spring"`,
+		`Tags["table"]: unexpected "4"`,
+		`note: expected "ring twice", got "ring once"`,
+	}, differences)
+}
+
+func Test_Diff_Should_Skip_Ignored_Fields(t *testing.T) {
+	type event struct {
+		ID   string
+		Name string
+	}
+
+	assert.Empty(t, diff(&event{ID: "1", Name: "pizza"}, &event{ID: "2", Name: "pizza"}, []string{"ID"}))
+}
+
+func Test_Diff_Should_Report_Different_Types_And_Nil_Values(t *testing.T) {
+	type event struct {
+		Name string
+	}
+
+	assert.Equal(t, []string{`value: expected *mediatrtest.event &{Name:pizza}, got string "pizza"`}, diff(&event{Name: "pizza"}, "pizza", nil))
+	assert.Equal(t, []string{"value: expected nil, got &{Name:pizza}"}, diff((*event)(nil), &event{Name: "pizza"}, nil))
+}
@@ -0,0 +1,223 @@
+// Package mediatrtest provides a fake mediator for the tests of handlers: New isolates the registrations of the
+// mediator for the duration of a test, records every Send and Publish call with its metadata, and the Stub and
+// Assert functions stub the downstream handlers and check the recorded calls. The calls are recorded by a hook of
+// the mediator, not by a pipeline behavior: the recording isn't listed by Describe, and isn't stopped by
+// ClearPipelineBehaviors or SkipBehavior.
+//
+// The mediator is global, so the tests using a fake mediator must not run in parallel.
+//
+// Example:
+//
+//	func Test_CreateProduct_Should_Publish_ProductCreated(t *testing.T) {
+//	    mediatrtest.New(t)
+//	    require.NoError(t, mediatr.RegisterRequestHandler[*CreateProductCommand, *CreateProductCommandResponse](handler))
+//	    mediatrtest.StubNotification[*ProductCreatedEvent](t, nil)
+//
+//	    _, err := mediatr.Send[*CreateProductCommand, *CreateProductCommandResponse](ctx, command)
+//
+//	    require.NoError(t, err)
+//	    mediatrtest.AssertPublished(t, func(event *ProductCreatedEvent) bool {
+//	        return event.Name == command.Name
+//	    })
+//	}
+package mediatrtest
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/mehdihadeli/go-mediatr/internal/registryhook"
+)
+
+// CallKind is the kind of a recorded call.
+type CallKind string
+
+const (
+	// SendCall is a Send or SendAsync call.
+	SendCall CallKind = "send"
+	// PublishCall is a Publish call.
+	PublishCall CallKind = "publish"
+)
+
+// Call is a recorded Send or Publish call, Response is nil for Publish calls. The Send calls of requests
+// without handler fail before reaching the recorder, and are not recorded.
+type Call struct {
+	Kind     CallKind
+	Message  interface{}
+	Response interface{}
+	Err      error
+	Metadata mediatr.Metadata
+}
+
+// Mediator is a fake mediator, recording the calls dispatched while the test runs.
+type Mediator struct {
+	t     testing.TB
+	mutex sync.Mutex
+	calls []*Call
+}
+
+var (
+	// fakes are the fake mediators of the running tests, the last one is the active one
+	fakes      []*Mediator
+	fakesMutex sync.Mutex
+)
+
+// New replaces the registrations of the mediator with empty ones for the duration of the test, and starts
+// recording the Send and Publish calls. The registrations in place before the test are restored by t.Cleanup.
+// The handlers under test are registered with the usual mediatr functions, or installed with mediatr.Install.
+func New(t testing.TB) *Mediator {
+	t.Helper()
+
+	m := &Mediator{t: t}
+	restore := registryhook.Isolate()
+	restoreObserver := registryhook.Observe(&observer{mediator: m})
+	t.Cleanup(func() {
+		restoreObserver()
+		restore()
+
+		fakesMutex.Lock()
+		defer fakesMutex.Unlock()
+		fakes = slices.DeleteFunc(fakes, func(fake *Mediator) bool { return fake == m })
+	})
+
+	fakesMutex.Lock()
+	fakes = append(fakes, m)
+	fakesMutex.Unlock()
+
+	return m
+}
+
+// Calls returns the recorded calls, in dispatch order.
+func (m *Mediator) Calls() []Call {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	calls := make([]Call, 0, len(m.calls))
+	for _, call := range m.calls {
+		calls = append(calls, *call)
+	}
+
+	return calls
+}
+
+// Reset forgets the recorded calls, e.g. the calls made while arranging the test.
+func (m *Mediator) Reset() {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.calls = nil
+}
+
+// Sent returns the requests of type T sent while the test runs, in dispatch order.
+func Sent[T any](t testing.TB) []T {
+	t.Helper()
+
+	return messages[T](fakeOf(t), SendCall)
+}
+
+// Published returns the notifications of type T published while the test runs, in dispatch order.
+func Published[T any](t testing.TB) []T {
+	t.Helper()
+
+	return messages[T](fakeOf(t), PublishCall)
+}
+
+// Stub registers a handler of TRequest returning response and err.
+func Stub[TRequest any, TResponse any](t testing.TB, response TResponse, err error) {
+	t.Helper()
+
+	StubFunc(t, func(ctx context.Context, request TRequest) (TResponse, error) {
+		return response, err
+	})
+}
+
+// StubFunc registers a function as the handler of TRequest.
+func StubFunc[TRequest any, TResponse any](t testing.TB, fn func(ctx context.Context, request TRequest) (TResponse, error)) {
+	t.Helper()
+
+	fakeOf(t)
+	if err := mediatr.RegisterRequestHandlerFunc(fn); err != nil {
+		t.Fatalf("mediatrtest: %v", err)
+	}
+}
+
+// StubNotification registers a handler of TNotification returning err.
+func StubNotification[TNotification any](t testing.TB, err error) {
+	t.Helper()
+
+	fakeOf(t)
+	handler := func(ctx context.Context, notification TNotification) error {
+		return err
+	}
+	if err := mediatr.RegisterNotificationHandlerFunc(handler); err != nil {
+		t.Fatalf("mediatrtest: %v", err)
+	}
+}
+
+// fakeOf returns the fake mediator created for t or, for a subtest, the active fake mediator
+func fakeOf(t testing.TB) *Mediator {
+	t.Helper()
+
+	fakesMutex.Lock()
+	defer fakesMutex.Unlock()
+
+	for i := len(fakes) - 1; i >= 0; i-- {
+		if fakes[i].t == t {
+			return fakes[i]
+		}
+	}
+	if len(fakes) == 0 {
+		t.Fatal("mediatrtest: no fake mediator, call mediatrtest.New(t) first")
+	}
+
+	return fakes[len(fakes)-1]
+}
+
+func messages[T any](m *Mediator, kind CallKind) []T {
+	var matched []T
+	for _, call := range m.Calls() {
+		if message, ok := call.Message.(T); ok && call.Kind == kind {
+			matched = append(matched, message)
+		}
+	}
+
+	return matched
+}
+
+// record adds a call when it is dispatched, and returns a function completing it with its result
+func (m *Mediator) record(ctx context.Context, kind CallKind, message interface{}) func(response interface{}, err error) {
+	metadata, _ := mediatr.MetadataFromContext(ctx)
+	call := &Call{Kind: kind, Message: message, Metadata: metadata}
+
+	m.mutex.Lock()
+	m.calls = append(m.calls, call)
+	m.mutex.Unlock()
+
+	return func(response interface{}, err error) {
+		m.mutex.Lock()
+		defer m.mutex.Unlock()
+
+		call.Response, call.Err = response, err
+	}
+}
+
+// observer records the calls of a fake mediator. It observes the calls before their behaviors, so it records the
+// requests as sent, before any replacement, and it isn't a registration: it isn't described, cleared or skipped.
+type observer struct {
+	mediator *Mediator
+}
+
+func (o *observer) Sent(ctx context.Context, request interface{}) func(response interface{}, err error) {
+	return o.mediator.record(ctx, SendCall, request)
+}
+
+func (o *observer) Published(ctx context.Context, notification interface{}) func(err error) {
+	complete := o.mediator.record(ctx, PublishCall, notification)
+
+	return func(err error) {
+		complete(nil, err)
+	}
+}
@@ -0,0 +1,154 @@
+package mediatrtest
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_New_Should_Isolate_Registrations_During_Test(t *testing.T) {
+	require.NoError(t, mediatr.RegisterRequestHandler[*placeOrder, *orderPlacedResponse](&placeOrderHandler{}))
+	defer mediatr.ClearRequestRegistrations()
+
+	t.Run("isolated", func(t *testing.T) {
+		New(t)
+
+		_, err := mediatr.Send[*placeOrder, *orderPlacedResponse](context.Background(), &placeOrder{})
+
+		assert.ErrorContains(t, err, "no handler for request")
+	})
+
+	_, err := mediatr.Send[*placeOrder, *orderPlacedResponse](context.Background(), &placeOrder{Product: "pizza"})
+	assert.NoError(t, err, "the registrations should be restored after the test")
+}
+
+func Test_New_Should_Isolate_Installed_Modules(t *testing.T) {
+	for i := 0; i < 2; i++ {
+		t.Run(fmt.Sprintf("install %d", i), func(t *testing.T) {
+			New(t)
+
+			assert.NoError(t, mediatr.Install(&ordersModule{}))
+		})
+	}
+}
+
+func Test_Mediator_Should_Record_Send_And_Publish_Calls_With_Metadata(t *testing.T) {
+	m := New(t)
+	require.NoError(t, mediatr.RegisterRequestHandler[*placeOrder, *orderPlacedResponse](&placeOrderHandler{}))
+
+	_, err := mediatr.Send[*placeOrder, *orderPlacedResponse](context.Background(), &placeOrder{Product: "pizza"}, mediatr.WithUserID("bob"))
+
+	require.NoError(t, err)
+	AssertSent(t, func(command *placeOrder) bool { return command.Product == "pizza" })
+	AssertPublished(t, func(event *orderPlaced) bool { return event.Product == "pizza" })
+	AssertNotPublished[*orderCancelled](t, nil)
+	assert.Equal(t, []*orderPlaced{{Product: "pizza"}}, Published[*orderPlaced](t))
+
+	calls := m.Calls()
+	require.Len(t, calls, 2)
+	assert.Equal(t, SendCall, calls[0].Kind)
+	assert.Equal(t, &orderPlacedResponse{Product: "pizza"}, calls[0].Response)
+	assert.Equal(t, "bob", calls[0].Metadata.UserID)
+	assert.Equal(t, PublishCall, calls[1].Kind)
+	assert.Equal(t, calls[0].Metadata.MessageID, calls[1].Metadata.CausationID)
+	assert.Equal(t, "bob", calls[1].Metadata.UserID)
+}
+
+func Test_Mediator_Should_Record_Calls_Without_Registering_Behaviors(t *testing.T) {
+	m := New(t)
+	require.NoError(t, mediatr.RegisterRequestHandler[*placeOrder, *orderPlacedResponse](&placeOrderHandler{}))
+
+	assert.Empty(t, slices.Collect(mediatr.Describe().Behaviors()))
+	assert.NotContains(t, mediatr.Describe().DOT(), "recorder")
+	mediatr.ClearPipelineBehaviors()
+	_, err := mediatr.Send[*placeOrder, *orderPlacedResponse](context.Background(), &placeOrder{}, mediatr.SkipBehavior("mediatrtest.recorder"))
+
+	require.NoError(t, err)
+	assert.Len(t, m.Calls(), 2, "the send and the publish calls should be recorded")
+}
+
+func Test_Stub_Should_Return_Canned_Response_Or_Error(t *testing.T) {
+	New(t)
+	Stub[*placeOrder, *orderPlacedResponse](t, &orderPlacedResponse{Product: "stubbed"}, nil)
+	StubNotification[*orderPlaced](t, errors.New("broker down"))
+
+	response, err := mediatr.Send[*placeOrder, *orderPlacedResponse](context.Background(), &placeOrder{})
+	require.NoError(t, err)
+	assert.Equal(t, "stubbed", response.Product)
+
+	err = mediatr.Publish(context.Background(), &orderPlaced{})
+	assert.ErrorContains(t, err, "broker down")
+	assert.Len(t, Published[*orderPlaced](t), 1)
+}
+
+func Test_AssertPublished_Should_List_Recorded_Calls_On_Failure(t *testing.T) {
+	New(t)
+	require.NoError(t, mediatr.Publish(context.Background(), &orderPlaced{Product: "pizza"}))
+	recorder := &errorRecorder{TB: t}
+
+	ok := AssertPublished(recorder, func(event *orderPlaced) bool { return event.Product == "pasta" })
+
+	assert.False(t, ok)
+	assert.Equal(t, []string{
+		"mediatrtest: no publish call of *mediatrtest.orderPlaced matching the predicate\n" +
+			"recorded publish calls:\n  1. *mediatrtest.orderPlaced &{Product:pizza}",
+	}, recorder.errors)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type placeOrder struct {
+	Product string
+}
+
+type orderPlacedResponse struct {
+	Product string
+}
+
+type orderPlaced struct {
+	Product string
+}
+
+type orderCancelled struct {
+}
+
+type placeOrderHandler struct {
+}
+
+func (h *placeOrderHandler) Handle(ctx context.Context, command *placeOrder) (*orderPlacedResponse, error) {
+	if err := mediatr.Publish(ctx, &orderPlaced{Product: command.Product}); err != nil {
+		return nil, err
+	}
+
+	return &orderPlacedResponse{Product: command.Product}, nil
+}
+
+type ordersModule struct {
+}
+
+func (m *ordersModule) Name() string {
+	return "orders"
+}
+
+func (m *ordersModule) DependsOn() []string {
+	return nil
+}
+
+func (m *ordersModule) Register(r mediatr.Registrar) error {
+	return mediatr.AddRequestHandler[*placeOrder, *orderPlacedResponse](r, &placeOrderHandler{})
+}
+
+// errorRecorder records the errors of an assertion instead of failing the test
+type errorRecorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *errorRecorder) Errorf(format string, args ...interface{}) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,144 @@
+package mediatrtest
+
+import (
+	"context"
+	"math/rand/v2"
+	"sync"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+)
+
+// Scheduler is a deterministic mediatr.Executor: the tasks of the concurrent dispatches, the requests sent with
+// SendAsync and the notification handlers published WithParallelHandlers, are queued instead of started, and run
+// one at a time in an order drawn from a seeded random source. The same seed reproduces the same interleaving.
+//
+// The queued tasks run on the goroutine calling Step or Run, or waiting for them in Future.Await or Publish.
+// A task runs to completion, unless it waits for another task, which runs meanwhile.
+//
+// Example:
+//
+//	for seed := uint64(0); seed < 100; seed++ {
+//	    t.Run(fmt.Sprint(seed), func(t *testing.T) {
+//	        scheduler := mediatrtest.NewScheduler(t, seed)
+//	        first := mediatr.SendAsync[*ReserveStockCommand, *ReserveStockResponse](ctx, &ReserveStockCommand{Quantity: 3})
+//	        second := mediatr.SendAsync[*ReserveStockCommand, *ReserveStockResponse](ctx, &ReserveStockCommand{Quantity: 4})
+//	        scheduler.Run()
+//	        // check the invariants, t.Log(scheduler.Order()) shows the interleaving of a failing seed
+//	    })
+//	}
+type Scheduler struct {
+	mutex   sync.Mutex
+	random  *rand.Rand
+	pending []scheduledTask
+	order   []int
+	queued  int
+	// wakeup is signaled when a task is queued, for the waits whose work runs outside of the scheduler
+	wakeup chan struct{}
+}
+
+// scheduledTask is a queued task, id is its position in the queue order, starting from 1
+type scheduledTask struct {
+	id  int
+	run func()
+}
+
+// NewScheduler sets a deterministic executor seeded with seed for the duration of the test.
+// The default executor is restored by t.Cleanup.
+func NewScheduler(t testing.TB, seed uint64) *Scheduler {
+	t.Helper()
+
+	s := &Scheduler{
+		random: rand.New(rand.NewPCG(seed, seed)),
+		wakeup: make(chan struct{}, 1),
+	}
+	mediatr.SetExecutor(s)
+	t.Cleanup(func() {
+		mediatr.SetExecutor(nil)
+	})
+
+	return s
+}
+
+// Go queues task, it runs on a later Step.
+func (s *Scheduler) Go(task func()) {
+	s.mutex.Lock()
+	s.queued++
+	s.pending = append(s.pending, scheduledTask{id: s.queued, run: task})
+	s.mutex.Unlock()
+
+	select {
+	case s.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+// Wait runs the queued tasks until done is closed. If no task is queued, the waited work runs outside of the
+// scheduler, and Wait blocks until it is done, ctx is done or a task is queued.
+func (s *Scheduler) Wait(ctx context.Context, done <-chan struct{}) error {
+	for {
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		if s.Step() {
+			continue
+		}
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-s.wakeup:
+		}
+	}
+}
+
+// Step runs one of the queued tasks, drawn from the random source. Returns false if no task is queued.
+func (s *Scheduler) Step() bool {
+	s.mutex.Lock()
+	if len(s.pending) == 0 {
+		s.mutex.Unlock()
+		return false
+	}
+	index := s.random.IntN(len(s.pending))
+	task := s.pending[index]
+	s.pending = append(s.pending[:index], s.pending[index+1:]...)
+	s.order = append(s.order, task.id)
+	s.mutex.Unlock()
+
+	task.run()
+
+	return true
+}
+
+// Run runs the queued tasks, and the tasks they queue, until no task is queued. Returns the number of tasks run.
+func (s *Scheduler) Run() int {
+	count := 0
+	for s.Step() {
+		count++
+	}
+
+	return count
+}
+
+// Pending returns the number of queued tasks.
+func (s *Scheduler) Pending() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return len(s.pending)
+}
+
+// Order returns the ids of the tasks run so far, in the order they ran. The tasks are numbered from 1 in the
+// order they were queued.
+func (s *Scheduler) Order() []int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	return append([]int(nil), s.order...)
+}
@@ -0,0 +1,83 @@
+package mediatrtest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Scheduler_Should_Reproduce_Interleaving_Of_A_Seed(t *testing.T) {
+	orders := map[string]struct{}{}
+	for seed := uint64(0); seed < 20; seed++ {
+		first := publishInParallel(t, seed)
+		second := publishInParallel(t, seed)
+
+		assert.Equal(t, first, second, "seed %d should reproduce the same interleaving", seed)
+		orders[fmt.Sprint(first)] = struct{}{}
+	}
+
+	assert.Greater(t, len(orders), 1, "the seeds should produce different interleavings")
+}
+
+func Test_Scheduler_Should_Queue_Async_Sends_Until_Run(t *testing.T) {
+	New(t)
+	scheduler := NewScheduler(t, 1)
+	Stub[*placeOrder, *orderPlacedResponse](t, &orderPlacedResponse{Product: "pizza"}, nil)
+
+	future := mediatr.SendAsync[*placeOrder, *orderPlacedResponse](context.Background(), &placeOrder{})
+
+	assert.Equal(t, 1, scheduler.Pending())
+	assert.Empty(t, Sent[*placeOrder](t))
+	assert.Equal(t, 1, scheduler.Run())
+	response, err := future.Await(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "pizza", response.Product)
+	assert.False(t, scheduler.Step())
+}
+
+func Test_Scheduler_Should_Run_Queued_Tasks_While_Awaiting(t *testing.T) {
+	New(t)
+	scheduler := NewScheduler(t, 1)
+	// the handler awaits a request sent asynchronously, which runs while it waits
+	require.NoError(t, mediatr.RegisterRequestHandlerFunc(func(ctx context.Context, command *createProduct) (*createProductResponse, error) {
+		placed, err := mediatr.SendAsync[*placeOrder, *orderPlacedResponse](ctx, &placeOrder{Product: command.Name}).Await(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return &createProductResponse{Name: placed.Product}, nil
+	}))
+	StubFunc(t, func(ctx context.Context, command *placeOrder) (*orderPlacedResponse, error) {
+		return &orderPlacedResponse{Product: command.Product}, nil
+	})
+
+	response, err := mediatr.SendAsync[*createProduct, *createProductResponse](context.Background(), &createProduct{Name: "pizza"}).
+		Await(context.Background())
+
+	require.NoError(t, err)
+	assert.Equal(t, "pizza", response.Name)
+	assert.Equal(t, []int{1, 2}, scheduler.Order())
+}
+
+// publishInParallel publishes a notification to three handlers with a scheduler seeded with seed, and returns the
+// order the handlers ran in
+func publishInParallel(t *testing.T, seed uint64) []string {
+	var order []string
+	t.Run(fmt.Sprint(seed), func(t *testing.T) {
+		New(t)
+		NewScheduler(t, seed)
+		for _, name := range []string{"billing", "shipping", "email"} {
+			require.NoError(t, mediatr.RegisterNotificationHandlerFunc(func(ctx context.Context, event *orderPlaced) error {
+				order = append(order, name)
+				return nil
+			}))
+		}
+
+		require.NoError(t, mediatr.Publish(context.Background(), &orderPlaced{}, mediatr.WithParallelHandlers()))
+	})
+
+	return order
+}
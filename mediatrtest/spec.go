@@ -0,0 +1,158 @@
+package mediatrtest
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/pkg/errors"
+)
+
+// Given is a precondition of a Spec, applied in order before the request of the spec is sent.
+// It fails the test if it can't be applied.
+type Given func(t testing.TB)
+
+// Installed installs the modules registering the handlers under test.
+func Installed(modules ...mediatr.Module) Given {
+	return func(t testing.TB) {
+		t.Helper()
+
+		if err := mediatr.Install(modules...); err != nil {
+			t.Fatalf("mediatrtest: %v", err)
+		}
+	}
+}
+
+// Stubbed stubs the handler of TRequest, returning response and err.
+func Stubbed[TRequest any, TResponse any](response TResponse, err error) Given {
+	return func(t testing.TB) {
+		t.Helper()
+
+		Stub[TRequest, TResponse](t, response, err)
+	}
+}
+
+// StubbedNotification stubs a handler of TNotification, returning err.
+func StubbedNotification[TNotification any](err error) Given {
+	return func(t testing.TB) {
+		t.Helper()
+
+		StubNotification[TNotification](t, err)
+	}
+}
+
+// Notification publishes a prior notification, e.g. to build the state read by the handler under test.
+// The calls of the prior notifications are not compared with the expectations of the spec.
+func Notification[TNotification any](notification TNotification) Given {
+	return func(t testing.TB) {
+		t.Helper()
+
+		if err := mediatr.Publish(context.Background(), notification); err != nil {
+			t.Fatalf("mediatrtest: given notification %T: %v", notification, err)
+		}
+	}
+}
+
+// Spec is a Given/When/Then specification of a request handler: given the preconditions, when the request is sent,
+// then the handlers publish the expected notifications and return the expected response or error.
+//
+// The published notifications and the response are compared field by field, the failures list the differences:
+//
+//	mediatrtest: unexpected published notifications:
+//	  1. *events.ProductCreatedEvent:
+//	       Name: expected "pizza", got "pasta"
+//	  2. missing *events.ProductPricedEvent &{Price:12}
+//
+// Example:
+//
+//	mediatrtest.Spec[*CreateProductCommand, *CreateProductCommandResponse]{
+//	    Given: []mediatrtest.Given{mediatrtest.Installed(creatingproduct.NewModule())},
+//	    When:  &CreateProductCommand{Name: "pizza", Price: 12},
+//	    ThenPublished: []interface{}{
+//	        &events.ProductCreatedEvent{Name: "pizza", Price: 12},
+//	    },
+//	    Ignore: []string{"ProductID", "CreatedAt"},
+//	}.Run(t)
+type Spec[TRequest any, TResponse any] struct {
+	// Given are the preconditions, typically the installation of the handlers under test, stubs and prior notifications.
+	Given []Given
+	// When is the request sent to the mediator.
+	When TRequest
+	// ThenPublished are the notifications expected to be published while the request is handled, in publish order,
+	// including the notifications published by the notification handlers.
+	ThenPublished []interface{}
+	// ThenResponse is the expected response, it is not compared if it is the zero value.
+	ThenResponse TResponse
+	// ThenError is the expected error, matched with errors.Is or by its message. Nil expects the request to succeed.
+	ThenError error
+	// Ignore are the names of the fields left out of the comparisons, at any depth, e.g. generated ids and timestamps.
+	Ignore []string
+}
+
+// Run isolates the registrations of the mediator with New, applies the preconditions, sends the request and checks
+// the expectations. Returns whether the expectations are met.
+func (s Spec[TRequest, TResponse]) Run(t testing.TB) bool {
+	t.Helper()
+
+	m := New(t)
+	for _, given := range s.Given {
+		given(t)
+	}
+	m.Reset()
+
+	response, err := mediatr.Send[TRequest, TResponse](context.Background(), s.When)
+
+	ok := s.checkError(t, err)
+	if err == nil && !reflect.ValueOf(&s.ThenResponse).Elem().IsZero() {
+		if differences := diff(s.ThenResponse, response, s.Ignore); len(differences) > 0 {
+			t.Errorf("mediatrtest: unexpected response %T:\n  %s", response, strings.Join(differences, "\n  "))
+			ok = false
+		}
+	}
+	if differences := diffMessages(s.ThenPublished, messages[interface{}](m, PublishCall), s.Ignore); len(differences) > 0 {
+		t.Errorf("mediatrtest: unexpected published notifications:\n  %s", strings.Join(differences, "\n  "))
+		ok = false
+	}
+
+	return ok
+}
+
+func (s Spec[TRequest, TResponse]) checkError(t testing.TB, err error) bool {
+	t.Helper()
+
+	switch {
+	case s.ThenError == nil && err != nil:
+		t.Errorf("mediatrtest: unexpected error: %v", err)
+	case s.ThenError != nil && err == nil:
+		t.Errorf("mediatrtest: expected error %q, got none", s.ThenError)
+	case s.ThenError != nil && !errors.Is(err, s.ThenError) && !strings.Contains(err.Error(), s.ThenError.Error()):
+		t.Errorf("mediatrtest: expected error %q, got %q", s.ThenError, err)
+	default:
+		return true
+	}
+
+	return false
+}
+
+// diffMessages returns the differences between the expected and the actual messages, compared by position
+func diffMessages(expected []interface{}, actual []interface{}, ignore []string) []string {
+	var lines []string
+	for i := 0; i < max(len(expected), len(actual)); i++ {
+		switch {
+		case i >= len(actual):
+			lines = append(lines, fmt.Sprintf("%d. missing %T %+v", i+1, expected[i], expected[i]))
+		case i >= len(expected):
+			lines = append(lines, fmt.Sprintf("%d. unexpected %T %+v", i+1, actual[i], actual[i]))
+		default:
+			differences := diff(expected[i], actual[i], ignore)
+			if len(differences) > 0 {
+				lines = append(lines, fmt.Sprintf("%d. %T:\n       %s", i+1, actual[i], strings.Join(differences, "\n       ")))
+			}
+		}
+	}
+
+	return lines
+}
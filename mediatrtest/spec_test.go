@@ -0,0 +1,163 @@
+package mediatrtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mehdihadeli/go-mediatr"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func Test_Spec_Should_Pass_When_Expectations_Are_Met(t *testing.T) {
+	ok := Spec[*createProduct, *createProductResponse]{
+		Given: []Given{Installed(&productsModule{})},
+		When:  &createProduct{Name: "pizza", Price: 12},
+		ThenPublished: []interface{}{
+			&productCreated{Name: "pizza"},
+			&productPriced{Price: 12},
+		},
+		ThenResponse: &createProductResponse{Name: "pizza"},
+		Ignore:       []string{"ProductID", "CreatedAt"},
+	}.Run(t)
+
+	assert.True(t, ok)
+}
+
+func Test_Spec_Should_Not_Compare_Given_Notifications(t *testing.T) {
+	var created []*productCreated
+
+	ok := Spec[*createProduct, *createProductResponse]{
+		Given: []Given{
+			Installed(&productsModule{}),
+			func(t testing.TB) {
+				_ = mediatr.RegisterNotificationHandlerFunc(func(ctx context.Context, event *productCreated) error {
+					created = append(created, event)
+					return nil
+				})
+			},
+			Notification(&productCreated{Name: "pasta"}),
+		},
+		When:          &createProduct{Name: "pizza"},
+		ThenPublished: []interface{}{&productCreated{Name: "pizza"}, &productPriced{}},
+		Ignore:        []string{"ProductID", "CreatedAt"},
+	}.Run(t)
+
+	assert.True(t, ok)
+	assert.Len(t, created, 2)
+}
+
+func Test_Spec_Should_Report_Differences_Of_Published_Notifications(t *testing.T) {
+	recorder := &errorRecorder{TB: t}
+
+	ok := Spec[*createProduct, *createProductResponse]{
+		Given: []Given{Installed(&productsModule{})},
+		When:  &createProduct{Name: "pasta", Price: 12},
+		ThenPublished: []interface{}{
+			&productCreated{Name: "pizza"},
+		},
+		Ignore: []string{"ProductID", "CreatedAt"},
+	}.Run(recorder)
+
+	assert.False(t, ok)
+	assert.Equal(t, []string{
+		"mediatrtest: unexpected published notifications:\n" +
+			"  1. *mediatrtest.productCreated:\n" +
+			"       Name: expected \"pizza\", got \"pasta\"\n" +
+			"  2. unexpected *mediatrtest.productPriced &{Price:12}",
+	}, recorder.errors)
+}
+
+func Test_Spec_Should_Report_Differences_Of_Response(t *testing.T) {
+	recorder := &errorRecorder{TB: t}
+
+	ok := Spec[*createProduct, *createProductResponse]{
+		Given:         []Given{Installed(&productsModule{})},
+		When:          &createProduct{Name: "pasta"},
+		ThenPublished: []interface{}{&productCreated{Name: "pasta"}, &productPriced{}},
+		ThenResponse:  &createProductResponse{Name: "pizza"},
+		Ignore:        []string{"ProductID", "CreatedAt"},
+	}.Run(recorder)
+
+	assert.False(t, ok)
+	assert.Equal(t, []string{
+		"mediatrtest: unexpected response *mediatrtest.createProductResponse:\n" +
+			"  Name: expected \"pizza\", got \"pasta\"",
+	}, recorder.errors)
+}
+
+func Test_Spec_Should_Match_Expected_Error(t *testing.T) {
+	errOutOfStock := errors.New("out of stock")
+
+	ok := Spec[*placeOrder, *orderPlacedResponse]{
+		Given:     []Given{Stubbed[*placeOrder, *orderPlacedResponse](nil, errors.Wrap(errOutOfStock, "placing order"))},
+		When:      &placeOrder{Product: "pizza"},
+		ThenError: errOutOfStock,
+	}.Run(t)
+
+	assert.True(t, ok)
+}
+
+func Test_Spec_Should_Report_Unexpected_Error(t *testing.T) {
+	recorder := &errorRecorder{TB: t}
+
+	ok := Spec[*placeOrder, *orderPlacedResponse]{
+		Given: []Given{Stubbed[*placeOrder, *orderPlacedResponse](nil, errors.New("out of stock"))},
+		When:  &placeOrder{Product: "pizza"},
+	}.Run(recorder)
+
+	assert.False(t, ok)
+	assert.Equal(t, []string{"mediatrtest: unexpected error: handler error: out of stock"}, recorder.errors)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type createProduct struct {
+	Name  string
+	Price float64
+}
+
+type createProductResponse struct {
+	ProductID string
+	Name      string
+}
+
+type productCreated struct {
+	ProductID string
+	Name      string
+	CreatedAt time.Time
+}
+
+type productPriced struct {
+	Price float64
+}
+
+type createProductHandler struct {
+}
+
+func (h *createProductHandler) Handle(ctx context.Context, command *createProduct) (*createProductResponse, error) {
+	productID := time.Now().Format(time.RFC3339Nano)
+	if err := mediatr.Publish(ctx, &productCreated{ProductID: productID, Name: command.Name, CreatedAt: time.Now()}); err != nil {
+		return nil, err
+	}
+	if err := mediatr.Publish(ctx, &productPriced{Price: command.Price}); err != nil {
+		return nil, err
+	}
+
+	return &createProductResponse{ProductID: productID, Name: command.Name}, nil
+}
+
+type productsModule struct {
+}
+
+func (m *productsModule) Name() string {
+	return "products"
+}
+
+func (m *productsModule) DependsOn() []string {
+	return nil
+}
+
+func (m *productsModule) Register(r mediatr.Registrar) error {
+	return mediatr.AddRequestHandler[*createProduct, *createProductResponse](r, &createProductHandler{})
+}
@@ -0,0 +1,144 @@
+package mediatr
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"sync/atomic"
+	"time"
+)
+
+// Metadata is the envelope attached to every Send and Publish call. A new message ID is generated
+// for each call, the correlation ID is propagated from the parent call (or starts with the message ID
+// of the first call) and the causation ID is the message ID of the call whose handler made this call.
+//
+// Example:
+//
+//	func (h *ProductCreatedEventHandler) Handle(ctx context.Context, event *ProductCreatedEvent) error {
+//	    metadata, _ := mediatr.MetadataFromContext(ctx)
+//	    log.Printf("correlation: %s, caused by: %s", metadata.CorrelationID, metadata.CausationID)
+//	    return nil
+//	}
+type Metadata struct {
+	// MessageID is laid out like a UUID, but the ids of a process share a random prefix and end with a sequence number
+	MessageID     string                 `json:"messageId"`
+	CorrelationID string                 `json:"correlationId"`
+	CausationID   string                 `json:"causationId,omitempty"`
+	UserID        string                 `json:"userId,omitempty"`
+	Timestamp     time.Time              `json:"timestamp"`
+	Items         map[string]interface{} `json:"items,omitempty"`
+}
+
+type metadataKey struct{}
+
+// MetadataFromContext returns the metadata of the call currently being handled.
+// The Items map is shared with the call and should not be modified.
+func MetadataFromContext(ctx context.Context) (Metadata, bool) {
+	options, ok := ctx.Value(metadataKey{}).(*callOptions)
+	if !ok {
+		return Metadata{}, false
+	}
+
+	return *options.callMetadata(), true
+}
+
+// WithCorrelationID overrides the correlation ID of the call, instead of propagating the parent one.
+func WithCorrelationID(correlationID string) Option {
+	return func(o *callOptions) {
+		o.metadata.CorrelationID = correlationID
+	}
+}
+
+// WithCausationID overrides the causation ID of the call, instead of using the parent message ID.
+func WithCausationID(causationID string) Option {
+	return func(o *callOptions) {
+		o.metadata.CausationID = causationID
+	}
+}
+
+// WithUserID sets the identity of the user on whose behalf the call is made.
+// It is propagated to nested calls.
+func WithUserID(userID string) Option {
+	return func(o *callOptions) {
+		o.metadata.UserID = userID
+	}
+}
+
+// callMetadata returns the envelope of the call, built on its first read, so that the calls nobody reads the
+// metadata of don't pay for it. The options set by the caller take precedence over the values of the parent call.
+func (o *callOptions) callMetadata() *Metadata {
+	o.metadataOnce.Do(func() {
+		metadata := &o.metadata
+		metadata.MessageID = newMessageID()
+		if o.parent != nil {
+			parent := o.parent.callMetadata()
+			if metadata.CorrelationID == "" {
+				metadata.CorrelationID = parent.CorrelationID
+			}
+			if metadata.CausationID == "" {
+				metadata.CausationID = parent.MessageID
+			}
+			if metadata.UserID == "" {
+				metadata.UserID = parent.UserID
+			}
+			metadata.Items = mergeItems(parent.Items, metadata.Items)
+		}
+		if metadata.CorrelationID == "" {
+			metadata.CorrelationID = metadata.MessageID
+		}
+	})
+
+	return &o.metadata
+}
+
+// mergeItems returns the items of a call, the items of its parent overridden by its own
+func mergeItems(parent map[string]interface{}, items map[string]interface{}) map[string]interface{} {
+	if len(parent) == 0 {
+		return items
+	}
+
+	merged := make(map[string]interface{}, len(parent)+len(items))
+	for key, value := range parent {
+		merged[key] = value
+	}
+	for key, value := range items {
+		merged[key] = value
+	}
+
+	return merged
+}
+
+var (
+	// messageIDPrefix is the random part of the message ids, drawn once per process
+	messageIDPrefix = newMessageIDPrefix()
+	// messageSequence numbers the message ids of the process
+	messageSequence atomic.Uint64
+)
+
+const hexDigits = "0123456789abcdef"
+
+// newMessageID generates a message id laid out like a UUID, but it isn't a random version 4 UUID: the ids share
+// a random prefix drawn once per process, and end with a sequence number, so they are unique and sequential within
+// the process, and predictable from one another. They must not be used as secrets.
+func newMessageID() string {
+	var id [36]byte
+	copy(id[:], messageIDPrefix)
+	sequence := messageSequence.Add(1)
+	for i := len(id) - 1; i >= len(messageIDPrefix); i-- {
+		id[i] = hexDigits[sequence&0xf]
+		sequence >>= 4
+	}
+
+	return string(id[:])
+}
+
+// newMessageIDPrefix draws the random first 10 bytes of the message ids, formatted with their trailing dash. The
+// version and variant bits of a version 4 UUID are set, so that the ids parse as UUIDs.
+func newMessageIDPrefix() string {
+	var id [10]byte
+	_, _ = rand.Read(id[:])
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-", id[0:4], id[4:6], id[6:8], id[8:10])
+}
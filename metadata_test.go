@@ -0,0 +1,125 @@
+package mediatr
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_Should_Attach_Metadata_With_New_Message_And_Correlation_Id(t *testing.T) {
+	defer cleanup()
+	handler := &metadataCapturingHandler{}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	require.NoError(t, err)
+	first := handler.metadata
+
+	_, err = Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	require.NoError(t, err)
+	second := handler.metadata
+
+	assert.Regexp(t, "^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$", first.MessageID)
+	assert.NotEqual(t, first.MessageID, second.MessageID)
+	assert.Equal(t, first.MessageID[:24], second.MessageID[:24], "the ids of a process should share their random prefix")
+	assert.Equal(t, first.MessageID, first.CorrelationID, "top level call should start the correlation")
+	assert.Empty(t, first.CausationID)
+	assert.False(t, first.Timestamp.IsZero())
+}
+
+func Test_Publish_From_Handler_Should_Propagate_Correlation_And_Set_Causation(t *testing.T) {
+	defer cleanup()
+	requestHandler := &publishingRequestHandler{}
+	notificationHandler := &metadataCapturingNotificationHandler{}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](requestHandler))
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](notificationHandler))
+
+	_, err := Send[*RequestTest, *ResponseTest](
+		context.Background(),
+		&RequestTest{},
+		WithCorrelationID("correlation-1"),
+		WithUserID("user-1"),
+		WithMetadata("tenant", "acme"),
+	)
+	require.NoError(t, err)
+
+	command := requestHandler.metadata
+	event := notificationHandler.metadata
+	assert.Equal(t, "correlation-1", command.CorrelationID)
+	assert.Equal(t, "correlation-1", event.CorrelationID)
+	assert.Equal(t, command.MessageID, event.CausationID)
+	assert.NotEqual(t, command.MessageID, event.MessageID)
+	assert.Equal(t, "user-1", event.UserID)
+	assert.Equal(t, "acme", event.Items["tenant"])
+}
+
+func Test_MetadataFromContext_Should_Return_False_Outside_Of_Call(t *testing.T) {
+	_, ok := MetadataFromContext(context.Background())
+	assert.False(t, ok)
+}
+
+func Test_Send_Should_Not_Build_Metadata_If_Not_Read(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	sequence := messageSequence.Load()
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{}, WithUserID("user-1"))
+
+	require.NoError(t, err)
+	assert.Equal(t, sequence, messageSequence.Load(), "no message id should be generated")
+	if !raceEnabled {
+		allocs := testing.AllocsPerRun(100, func() {
+			applyCallOptions(context.Background(), nil)
+		})
+		assert.LessOrEqual(t, allocs, 1.0, "call context only")
+	}
+}
+
+func Test_MetadataFromContext_Should_Build_Parent_Metadata_Read_By_Nested_Call(t *testing.T) {
+	parentCtx, _ := applyCallOptions(context.Background(), []Option{WithUserID("user-1"), WithMetadata("tenant", "acme")})
+	childCtx, _ := applyCallOptions(parentCtx, []Option{WithMetadata("user", "bob")})
+
+	child, _ := MetadataFromContext(childCtx)
+	parent, _ := MetadataFromContext(parentCtx)
+
+	assert.Equal(t, parent.MessageID, child.CausationID)
+	assert.Equal(t, parent.MessageID, child.CorrelationID)
+	assert.Equal(t, "user-1", child.UserID)
+	assert.Equal(t, map[string]interface{}{"tenant": "acme", "user": "bob"}, child.Items)
+	assert.Equal(t, map[string]interface{}{"tenant": "acme"}, parent.Items, "parent items should not be modified")
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type metadataCapturingHandler struct {
+	metadata Metadata
+}
+
+func (c *metadataCapturingHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	c.metadata, _ = MetadataFromContext(ctx)
+
+	return &ResponseTest{}, nil
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type publishingRequestHandler struct {
+	metadata Metadata
+}
+
+func (c *publishingRequestHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	c.metadata, _ = MetadataFromContext(ctx)
+
+	return &ResponseTest{}, Publish(ctx, &NotificationTest{})
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type metadataCapturingNotificationHandler struct {
+	metadata Metadata
+}
+
+func (c *metadataCapturingNotificationHandler) Handle(ctx context.Context, notification *NotificationTest) error {
+	c.metadata, _ = MetadataFromContext(ctx)
+
+	return nil
+}
@@ -0,0 +1,272 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Module groups the registrations of a feature, so each feature package can export its own wiring.
+//
+// Example:
+//
+//	type Module struct{ productRepository *repository.InMemoryProductRepository }
+//
+//	func (m *Module) Register(r mediatr.Registrar) error {
+//	    return mediatr.AddRequestHandler[*CreateProductCommand, *CreateProductCommandResponse](r, NewCreateProductCommandHandler(m.productRepository))
+//	}
+type Module interface {
+	Register(r Registrar) error
+}
+
+// NamedModule can be implemented by a Module that other modules depend on, or that depends on other modules.
+// Install registers a module after the modules listed by DependsOn.
+type NamedModule interface {
+	Module
+	Name() string
+	DependsOn() []string
+}
+
+// Registrar collects the registrations of a Module, with the Add functions like AddRequestHandler.
+type Registrar interface {
+	addRequestHandler(requestType reflect.Type, registration *handlerRegistration) error
+	addNotificationHandler(notificationType reflect.Type, registration *handlerRegistration) error
+	addPipelineBehavior(behavior PipelineBehavior) error
+	addNotificationBehavior(behavior NotificationPipelineBehavior) error
+}
+
+var (
+	installedModules = map[string]struct{}{}
+	installMutex     sync.Mutex
+)
+
+// Install registers the modules in the mediator, all or nothing: if a module returns an error, or one of
+// its registrations conflicts with an existing one, nothing is registered. Modules implementing NamedModule
+// are registered after the modules they depend on, the other modules are registered in the given order.
+//
+// Example:
+//
+//	err := mediatr.Install(
+//	    behaviours.NewModule(),
+//	    creatingproduct.NewModule(productRepository),
+//	    gettingproductbyid.NewModule(productRepository),
+//	)
+func Install(modules ...Module) error {
+	installMutex.Lock()
+	defer installMutex.Unlock()
+
+	ordered, err := orderModules(modules)
+	if err != nil {
+		return err
+	}
+
+	// the registrations of the modules are staged in an empty snapshot
+	staging := emptyRegistry.clone()
+	for _, module := range ordered {
+		if err := module.Register(staging); err != nil {
+			return errors.Wrapf(err, "module %s registration failed", moduleName(module))
+		}
+	}
+
+	if err := applyRegistry(staging); err != nil {
+		return err
+	}
+
+	for _, module := range ordered {
+		if named, ok := module.(NamedModule); ok {
+			installedModules[named.Name()] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+// AddRequestHandler adds a request handler to the registrations of a module.
+func AddRequestHandler[TRequest any, TResponse any](r Registrar, handler RequestHandler[TRequest, TResponse]) error {
+	return r.addRequestHandler(reflect.TypeOf(*new(TRequest)), withResponseType[TResponse](newInstanceRegistration(handler)))
+}
+
+// AddRequestHandlerFactory adds a request handler factory to the registrations of a module.
+func AddRequestHandlerFactory[TRequest any, TResponse any](
+	r Registrar,
+	factory RequestHandlerFactory[TRequest, TResponse],
+	opts ...RegistrationOption,
+) error {
+	registration := newFactoryRegistration(factory, func(context.Context) (interface{}, error) {
+		return factory(), nil
+	}, opts)
+
+	return r.addRequestHandler(reflect.TypeOf(*new(TRequest)), withResponseType[TResponse](registration))
+}
+
+// AddRequestHandlerContextFactory adds a request handler factory building the handlers from the request context to
+// the registrations of a module.
+func AddRequestHandlerContextFactory[TRequest any, TResponse any](
+	r Registrar,
+	factory RequestHandlerContextFactory[TRequest, TResponse],
+	opts ...RegistrationOption,
+) error {
+	registration := newFactoryRegistration(factory, func(ctx context.Context) (interface{}, error) {
+		return factory(ctx)
+	}, opts)
+
+	return r.addRequestHandler(reflect.TypeOf(*new(TRequest)), withResponseType[TResponse](registration))
+}
+
+// AddRequestHandlerFunc adds a request handler function to the registrations of a module.
+func AddRequestHandlerFunc[TRequest any, TResponse any](r Registrar, fn func(ctx context.Context, request TRequest) (TResponse, error)) error {
+	return AddRequestHandler[TRequest, TResponse](r, requestHandlerFunc[TRequest, TResponse](fn))
+}
+
+// AddNotificationHandler adds a notification handler to the registrations of a module.
+func AddNotificationHandler[TEvent any](r Registrar, handler NotificationHandler[TEvent]) error {
+	return r.addNotificationHandler(reflect.TypeOf(*new(TEvent)), newInstanceRegistration(handler))
+}
+
+// AddNotificationHandlerFactory adds a notification handler factory to the registrations of a module.
+func AddNotificationHandlerFactory[TEvent any](r Registrar, factory NotificationHandlerFactory[TEvent], opts ...RegistrationOption) error {
+	return r.addNotificationHandler(reflect.TypeOf(*new(TEvent)), newFactoryRegistration(factory, func(context.Context) (interface{}, error) {
+		return factory(), nil
+	}, opts))
+}
+
+// AddNotificationHandlerContextFactory adds a notification handler factory building the handlers from the notification
+// context to the registrations of a module.
+func AddNotificationHandlerContextFactory[TEvent any](
+	r Registrar,
+	factory NotificationHandlerContextFactory[TEvent],
+	opts ...RegistrationOption,
+) error {
+	return r.addNotificationHandler(reflect.TypeOf(*new(TEvent)), newFactoryRegistration(factory, func(ctx context.Context) (interface{}, error) {
+		return factory(ctx)
+	}, opts))
+}
+
+// AddNotificationHandlerFunc adds a notification handler function to the registrations of a module.
+func AddNotificationHandlerFunc[TEvent any](r Registrar, fn func(ctx context.Context, notification TEvent) error) error {
+	return AddNotificationHandler[TEvent](r, notificationHandlerFunc[TEvent](fn))
+}
+
+// AddPipelineBehaviors adds pipeline behaviors to the registrations of a module.
+func AddPipelineBehaviors(r Registrar, behaviors ...PipelineBehavior) error {
+	for _, behavior := range behaviors {
+		if err := r.addPipelineBehavior(behavior); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AddNotificationPipelineBehaviors adds notification pipeline behaviors to the registrations of a module.
+func AddNotificationPipelineBehaviors(r Registrar, behaviors ...NotificationPipelineBehavior) error {
+	for _, behavior := range behaviors {
+		if err := r.addNotificationBehavior(behavior); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// applyRegistry adds the staged registrations to the mediator, or none of them if one conflicts with an existing registration
+func applyRegistry(staging *registrySnapshot) error {
+	return updateRegistry(func(next *registrySnapshot) error {
+		for _, behavior := range staging.behaviors {
+			if err := next.addPipelineBehavior(behavior); err != nil {
+				return errors.Errorf("behavior %s already registered", BehaviorName(behavior))
+			}
+		}
+
+		for _, behavior := range staging.notificationBehaviors {
+			if err := next.addNotificationBehavior(behavior); err != nil {
+				return errors.Errorf("notification behavior %T already registered", behavior)
+			}
+		}
+
+		for requestType, registration := range staging.requests {
+			if err := next.addRequestHandler(requestType, registration); err != nil {
+				return err
+			}
+		}
+
+		for notificationType, registrations := range staging.notifications {
+			for _, registration := range registrations {
+				if err := next.addNotificationHandler(notificationType, registration); err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// orderModules sorts the modules so that each named module comes after the modules it depends on,
+// keeping the given order otherwise
+func orderModules(modules []Module) ([]Module, error) {
+	byName := map[string]int{}
+	for i, module := range modules {
+		if named, ok := module.(NamedModule); ok {
+			if _, exists := byName[named.Name()]; exists {
+				return nil, errors.Errorf("module %s is installed twice", named.Name())
+			}
+			byName[named.Name()] = i
+		}
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+	state := make([]int, len(modules))
+	ordered := make([]Module, 0, len(modules))
+
+	var visit func(index int, path []string) error
+	visit = func(index int, path []string) error {
+		module := modules[index]
+		switch state[index] {
+		case visited:
+			return nil
+		case visiting:
+			return errors.Errorf("modules have a dependency cycle: %s", strings.Join(append(path, moduleName(module)), " -> "))
+		}
+		state[index] = visiting
+
+		if named, ok := module.(NamedModule); ok {
+			for _, dependency := range named.DependsOn() {
+				if dependencyIndex, ok := byName[dependency]; ok {
+					if err := visit(dependencyIndex, append(path, named.Name())); err != nil {
+						return err
+					}
+				} else if _, installed := installedModules[dependency]; !installed {
+					return errors.Errorf("module %s depends on module %s, which is not installed", named.Name(), dependency)
+				}
+			}
+		}
+
+		state[index] = visited
+		ordered = append(ordered, module)
+
+		return nil
+	}
+
+	for i := range modules {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
+
+func moduleName(module Module) string {
+	if named, ok := module.(NamedModule); ok {
+		return named.Name()
+	}
+
+	return reflect.TypeOf(module).String()
+}
@@ -0,0 +1,233 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testModule is a module registering the handlers and behaviors of its register function
+type testModule struct {
+	name      string
+	dependsOn []string
+	register  func(r Registrar) error
+}
+
+func (m *testModule) Name() string {
+	return m.name
+}
+
+func (m *testModule) DependsOn() []string {
+	return m.dependsOn
+}
+
+func (m *testModule) Register(r Registrar) error {
+	return m.register(r)
+}
+
+// unnamedModule is a module without name and dependencies
+type unnamedModule func(r Registrar) error
+
+func (m unnamedModule) Register(r Registrar) error {
+	return m(r)
+}
+
+func Test_Install_Should_Register_Modules(t *testing.T) {
+	defer cleanup()
+	requests := unnamedModule(func(r Registrar) error {
+		if err := AddRequestHandler[*RequestTest, *ResponseTest](r, &echoRequestHandler{}); err != nil {
+			return err
+		}
+		return AddPipelineBehaviors(r, PipelineBehaviorFunc(upperCaseBehavior))
+	})
+	calls := 0
+	notifications := unnamedModule(func(r Registrar) error {
+		return AddNotificationHandlerFunc(r, func(ctx context.Context, notification *NotificationTest) error {
+			calls++
+			return nil
+		})
+	})
+
+	require.NoError(t, Install(requests, notifications))
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, "TEST", response.Data)
+	require.NoError(t, Publish(context.Background(), &NotificationTest{}))
+	assert.Equal(t, 1, calls)
+}
+
+func Test_Install_Should_Register_Context_Factories_And_Notification_Behaviors(t *testing.T) {
+	defer cleanup()
+	var calls []string
+	module := unnamedModule(func(r Registrar) error {
+		err := AddRequestHandlerContextFactory(r, func(ctx context.Context) (RequestHandler[*RequestTest2, *ResponseTest2], error) {
+			calls = append(calls, "request factory")
+			return &RequestTestHandler2{}, nil
+		})
+		if err != nil {
+			return err
+		}
+		err = AddNotificationHandlerContextFactory(r, func(ctx context.Context) (NotificationHandler[*NotificationTest2], error) {
+			calls = append(calls, "notification factory")
+			return &NotificationTestHandler2{}, nil
+		}, WithLifetime(Singleton))
+		if err != nil {
+			return err
+		}
+		return AddNotificationPipelineBehaviors(r, &tracingNotificationBehaviourTest{calls: &calls})
+	})
+
+	require.NoError(t, Install(module))
+
+	_, err := Send[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{})
+	require.NoError(t, err)
+	require.NoError(t, Publish(context.Background(), &NotificationTest2{}))
+	assert.Equal(t, []string{"request factory", "tracing", "notification factory"}, calls)
+}
+
+func Test_Install_Should_Register_Nothing_If_A_Module_Fails(t *testing.T) {
+	defer cleanup()
+	valid := unnamedModule(func(r Registrar) error {
+		if err := AddRequestHandler[*RequestTest, *ResponseTest](r, &echoRequestHandler{}); err != nil {
+			return err
+		}
+		if err := AddNotificationHandler[*NotificationTest](r, &NotificationTestHandler{}); err != nil {
+			return err
+		}
+		err := AddRequestHandlerContextFactory(r, func(ctx context.Context) (RequestHandler[*RequestTest2, *ResponseTest2], error) {
+			return &RequestTestHandler2{}, nil
+		})
+		if err != nil {
+			return err
+		}
+		err = AddNotificationHandlerContextFactory(r, func(ctx context.Context) (NotificationHandler[*NotificationTest2], error) {
+			return &NotificationTestHandler2{}, nil
+		})
+		if err != nil {
+			return err
+		}
+		if err := AddNotificationPipelineBehaviors(r, &tracingNotificationBehaviourTest{calls: &[]string{}}); err != nil {
+			return err
+		}
+		return AddPipelineBehaviors(r, &PipelineBehaviourTest{})
+	})
+	failing := &testModule{name: "failing", register: func(r Registrar) error {
+		return errors.New("missing configuration")
+	}}
+
+	err := Install(valid, failing)
+
+	assert.EqualError(t, err, "module failing registration failed: missing configuration")
+	assert.Equal(t, 0, countRequestHandlers())
+	assert.Equal(t, 0, countNotificationHandlers(reflect.TypeOf(&NotificationTest{})))
+	assert.Equal(t, 0, countNotificationHandlers(reflect.TypeOf(&NotificationTest2{})))
+	assert.Empty(t, loadRegistry().behaviors)
+	assert.Empty(t, loadRegistry().notificationBehaviors)
+}
+
+func Test_Install_Should_Register_Nothing_If_A_Registration_Conflicts(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&RequestTestHandler{}))
+	module := unnamedModule(func(r Registrar) error {
+		if err := AddRequestHandler[*RequestTest2, *ResponseTest2](r, &RequestTestHandler2{}); err != nil {
+			return err
+		}
+		return AddRequestHandler[*RequestTest, *ResponseTest](r, &echoRequestHandler{})
+	})
+
+	err := Install(module)
+
+	assert.EqualError(t, err, "handler already exists for type *mediatr.RequestTest")
+	assert.Equal(t, 1, countRequestHandlers())
+	_, err = Send[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{})
+	assert.ErrorContains(t, err, "no handler for request")
+}
+
+func Test_Install_Should_Register_Nothing_If_A_Notification_Behavior_Conflicts(t *testing.T) {
+	defer cleanup()
+	var calls []string
+	require.NoError(t, RegisterNotificationPipelineBehaviors(&tracingNotificationBehaviourTest{calls: &calls}))
+	module := unnamedModule(func(r Registrar) error {
+		err := AddRequestHandlerContextFactory(r, func(ctx context.Context) (RequestHandler[*RequestTest, *ResponseTest], error) {
+			return &echoRequestHandler{}, nil
+		})
+		if err != nil {
+			return err
+		}
+		return AddNotificationPipelineBehaviors(r, &tracingNotificationBehaviourTest{calls: &calls})
+	})
+
+	err := Install(module)
+
+	assert.EqualError(t, err, "notification behavior *mediatr.tracingNotificationBehaviourTest already registered")
+	assert.Equal(t, 0, countRequestHandlers())
+	assert.Len(t, loadRegistry().notificationBehaviors, 1)
+}
+
+func Test_Install_Should_Return_Error_For_Duplicate_Registrations_Across_Modules(t *testing.T) {
+	defer cleanup()
+	register := func(r Registrar) error {
+		return AddRequestHandler[*RequestTest, *ResponseTest](r, &echoRequestHandler{})
+	}
+
+	err := Install(unnamedModule(register), unnamedModule(register))
+
+	assert.EqualError(t, err, "module mediatr.unnamedModule registration failed: handler already exists for type *mediatr.RequestTest")
+	assert.Equal(t, 0, countRequestHandlers())
+}
+
+func Test_Install_Should_Register_Modules_After_Their_Dependencies(t *testing.T) {
+	defer cleanup()
+	var order []string
+	newModule := func(name string, dependsOn ...string) *testModule {
+		return &testModule{name: name, dependsOn: dependsOn, register: func(r Registrar) error {
+			order = append(order, name)
+			return nil
+		}}
+	}
+
+	err := Install(newModule("orders", "catalog", "shared"), newModule("catalog", "shared"), newModule("shared"))
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"shared", "catalog", "orders"}, order)
+}
+
+func Test_Install_Should_Accept_Dependencies_Installed_Before(t *testing.T) {
+	defer cleanup()
+	noop := func(r Registrar) error { return nil }
+	require.NoError(t, Install(&testModule{name: "shared", register: noop}))
+
+	err := Install(&testModule{name: "catalog", dependsOn: []string{"shared"}, register: noop})
+
+	assert.NoError(t, err)
+}
+
+func Test_Install_Should_Return_Error_For_Missing_Dependency(t *testing.T) {
+	defer cleanup()
+	module := &testModule{name: "catalog", dependsOn: []string{"shared"}, register: func(r Registrar) error {
+		return AddRequestHandler[*RequestTest, *ResponseTest](r, &echoRequestHandler{})
+	}}
+
+	err := Install(module)
+
+	assert.EqualError(t, err, "module catalog depends on module shared, which is not installed")
+	assert.Equal(t, 0, countRequestHandlers())
+}
+
+func Test_Install_Should_Return_Error_For_Dependency_Cycle(t *testing.T) {
+	defer cleanup()
+	noop := func(r Registrar) error { return nil }
+
+	err := Install(
+		&testModule{name: "a", dependsOn: []string{"b"}, register: noop},
+		&testModule{name: "b", dependsOn: []string{"c"}, register: noop},
+		&testModule{name: "c", dependsOn: []string{"a"}, register: noop},
+	)
+
+	assert.EqualError(t, err, "modules have a dependency cycle: a -> b -> c -> a")
+}
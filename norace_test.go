@@ -0,0 +1,5 @@
+//go:build !race
+
+package mediatr
+
+const raceEnabled = false
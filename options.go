@@ -0,0 +1,158 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Option configures a single dispatch call made with Send, SendAsync or Publish.
+//
+// Example:
+//
+//	response, err := mediatr.Send[*MyRequest, *MyResponse](ctx, &MyRequest{},
+//	    mediatr.WithTimeout(5*time.Second),
+//	    mediatr.WithMetadata("tenant", "acme"),
+//	    mediatr.SkipBehavior("cache"),
+//	)
+type Option func(*callOptions)
+
+// NamedBehavior can be implemented by a PipelineBehavior to choose the name used by SkipBehavior.
+// Behaviors that don't implement it are named after their type, e.g. "RequestLoggerBehaviour".
+type NamedBehavior interface {
+	Name() string
+}
+
+type callOptions struct {
+	timeout          time.Duration
+	skippedBehaviors map[string]struct{}
+	parallelHandlers bool
+	// metadata holds the values set by the options until the metadata is built by callMetadata
+	metadata     Metadata
+	metadataOnce sync.Once
+	parent       *callOptions
+}
+
+// WithTimeout bounds the whole pipeline, behaviors and handler included, by the given duration.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *callOptions) {
+		o.timeout = timeout
+	}
+}
+
+// WithMetadata attaches a key/value pair to the Items of the call metadata. Behaviors, handlers
+// and any nested call made with the handler context can read it with MetadataValue.
+func WithMetadata(key string, value interface{}) Option {
+	return func(o *callOptions) {
+		if o.metadata.Items == nil {
+			o.metadata.Items = map[string]interface{}{}
+		}
+		o.metadata.Items[key] = value
+	}
+}
+
+// SkipBehavior excludes the pipeline behaviors with the given names from the call.
+func SkipBehavior(names ...string) Option {
+	return func(o *callOptions) {
+		if o.skippedBehaviors == nil {
+			o.skippedBehaviors = map[string]struct{}{}
+		}
+		for _, name := range names {
+			o.skippedBehaviors[name] = struct{}{}
+		}
+	}
+}
+
+// WithParallelHandlers runs the notification handlers of a Publish call concurrently on the Executor, instead of
+// one after the other. Publish waits for all of them, and returns the error of the first failing handler in
+// registration order.
+func WithParallelHandlers() Option {
+	return func(o *callOptions) {
+		o.parallelHandlers = true
+	}
+}
+
+// MetadataValue returns the metadata value attached to the current call with WithMetadata.
+// It returns false if the key doesn't exist or the value is not of type T.
+//
+// Example:
+//
+//	tenant, ok := mediatr.MetadataValue[string](ctx, "tenant")
+func MetadataValue[T any](ctx context.Context, key string) (T, bool) {
+	options, ok := ctx.Value(metadataKey{}).(*callOptions)
+	if !ok {
+		return *new(T), false
+	}
+
+	value, ok := options.callMetadata().Items[key].(T)
+
+	return value, ok
+}
+
+// BehaviorName returns the name of a pipeline behavior as matched by SkipBehavior.
+func BehaviorName(behavior PipelineBehavior) string {
+	if named, ok := behavior.(NamedBehavior); ok {
+		return named.Name()
+	}
+
+	behaviorType := reflect.TypeOf(behavior)
+	for behaviorType.Kind() == reflect.Pointer {
+		behaviorType = behaviorType.Elem()
+	}
+
+	return behaviorType.Name()
+}
+
+// callContext is the context of a Send or Publish call, carrying the options and the metadata of the call, and the
+// scope of a top-level call. The scope is embedded, a call that resolves no Scoped or disposable handler doesn't
+// allocate it.
+type callContext struct {
+	context.Context
+	options   callOptions
+	scope     Scope
+	ownsScope bool
+}
+
+func (c *callContext) Value(key interface{}) interface{} {
+	switch key.(type) {
+	case metadataKey:
+		return &c.options
+	case scopeKey:
+		if c.ownsScope {
+			return &c.scope
+		}
+	}
+
+	return c.Context.Value(key)
+}
+
+// applyCallOptions returns the context of a new call, its metadata is derived from the parent call metadata and
+// the given options when first read. The call begins a scope if ctx doesn't have one.
+func applyCallOptions(ctx context.Context, opts []Option) (*callContext, *callOptions) {
+	call := &callContext{Context: ctx}
+	call.options.metadata.Timestamp = time.Now().UTC()
+	call.options.parent, _ = ctx.Value(metadataKey{}).(*callOptions)
+	_, hasScope := ScopeFromContext(ctx)
+	call.ownsScope = !hasScope
+	for _, opt := range opts {
+		opt(&call.options)
+	}
+
+	return call, &call.options
+}
+
+func (o *callOptions) filterBehaviors(behaviors []PipelineBehavior) []PipelineBehavior {
+	if len(o.skippedBehaviors) == 0 {
+		return behaviors
+	}
+
+	filtered := behaviors[:0]
+	for _, behavior := range behaviors {
+		if _, skipped := o.skippedBehaviors[BehaviorName(behavior)]; !skipped {
+			filtered = append(filtered, behavior)
+		}
+	}
+
+	return filtered
+}
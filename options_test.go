@@ -0,0 +1,118 @@
+package mediatr
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_Should_Expose_Metadata_To_Behaviors_And_Handler(t *testing.T) {
+	defer cleanup()
+	behavior := &metadataReaderBehavior{}
+	handler := &metadataReaderHandler{}
+	require.NoError(t, RegisterRequestPipelineBehaviors(behavior))
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{}, WithMetadata("tenant", "acme"))
+
+	require.NoError(t, err)
+	assert.Equal(t, "acme", behavior.tenant)
+	assert.Equal(t, "acme", handler.tenant)
+}
+
+func Test_MetadataValue_Should_Return_False_For_Missing_Key_Or_Wrong_Type(t *testing.T) {
+	ctx, _ := applyCallOptions(context.Background(), []Option{WithMetadata("retries", 3)})
+
+	_, ok := MetadataValue[string](ctx, "retries")
+	assert.False(t, ok, "value of another type should not be returned")
+
+	_, ok = MetadataValue[int](ctx, "missing")
+	assert.False(t, ok, "missing key should not be returned")
+
+	retries, ok := MetadataValue[int](ctx, "retries")
+	assert.True(t, ok)
+	assert.Equal(t, 3, retries)
+
+	_, ok = MetadataValue[int](context.Background(), "retries")
+	assert.False(t, ok, "context without options should not have metadata")
+}
+
+func Test_Send_Should_Inherit_Metadata_Of_Parent_Call(t *testing.T) {
+	ctx, _ := applyCallOptions(context.Background(), []Option{WithMetadata("tenant", "acme")})
+	ctx, _ = applyCallOptions(ctx, []Option{WithMetadata("user", "bob")})
+
+	tenant, _ := MetadataValue[string](ctx, "tenant")
+	user, _ := MetadataValue[string](ctx, "user")
+	assert.Equal(t, "acme", tenant)
+	assert.Equal(t, "bob", user)
+}
+
+func Test_Send_Should_Skip_Behaviors_By_Name(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestPipelineBehaviors(&PipelineBehaviourTest{}, &namedPipelineBehaviour{}))
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&RequestTestHandler{}))
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{}, SkipBehavior("cache", "PipelineBehaviourTest"))
+	require.NoError(t, err)
+
+	testMutex.Lock()
+	defer testMutex.Unlock()
+	assert.NotContains(t, testData, "PipelineBehaviourTest")
+	assert.NotContains(t, testData, "cache")
+	assert.Contains(t, testData, "RequestTestHandler")
+}
+
+func Test_Send_Should_Apply_Timeout_To_Pipeline(t *testing.T) {
+	defer cleanup()
+	handler := &blockingRequestHandler{started: make(chan struct{}), stopped: make(chan error, 1)}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{}, WithTimeout(10*time.Millisecond))
+
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorIs(t, <-handler.stopped, context.DeadlineExceeded)
+}
+
+func Test_BehaviorName_Should_Use_Name_Method_Or_Type_Name(t *testing.T) {
+	assert.Equal(t, "cache", BehaviorName(&namedPipelineBehaviour{}))
+	assert.Equal(t, "PipelineBehaviourTest", BehaviorName(&PipelineBehaviourTest{}))
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type namedPipelineBehaviour struct {
+}
+
+func (c *namedPipelineBehaviour) Name() string {
+	return "cache"
+}
+
+func (c *namedPipelineBehaviour) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	testData = append(testData, "cache")
+
+	return next(ctx)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type metadataReaderBehavior struct {
+	tenant string
+}
+
+func (c *metadataReaderBehavior) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	c.tenant, _ = MetadataValue[string](ctx, "tenant")
+
+	return next(ctx)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type metadataReaderHandler struct {
+	tenant string
+}
+
+func (c *metadataReaderHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	c.tenant, _ = MetadataValue[string](ctx, "tenant")
+
+	return &ResponseTest{}, nil
+}
@@ -0,0 +1,105 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+)
+
+var errContinuationAfterReturn = errors.New("pipeline continuation called after the behavior returned")
+
+// pipeline is the behavior chain of a request type, compiled once per registry snapshot.
+type pipeline[TRequest any, TResponse any] struct {
+	behaviors []PipelineBehavior
+}
+
+// pipelineCall is the state of a call through a pipeline. The request of each link is bound to the continuation
+// passed to its behavior, so that a behavior can call next concurrently with different replacements.
+type pipelineCall[TRequest any, TResponse any] struct {
+	behaviors []PipelineBehavior
+	handler   RequestHandler[TRequest, TResponse]
+	returned  atomic.Bool
+}
+
+// compilePipeline compiles the chain of behaviors, in registration order
+func compilePipeline[TRequest any, TResponse any](behaviors []PipelineBehavior) *pipeline[TRequest, TResponse] {
+	return &pipeline[TRequest, TResponse]{behaviors: behaviors}
+}
+
+// handle passes the request through the behaviors to the handler.
+// A continuation called after the call returned fails with errContinuationAfterReturn.
+func (p *pipeline[TRequest, TResponse]) handle(
+	ctx context.Context,
+	handler RequestHandler[TRequest, TResponse],
+	request TRequest,
+) (interface{}, error) {
+	call := &pipelineCall[TRequest, TResponse]{behaviors: p.behaviors, handler: handler}
+	defer call.returned.Store(true)
+
+	return call.invoke(ctx, 0, request)
+}
+
+// invoke calls the behavior at index with request, or the handler after the last behavior
+func (c *pipelineCall[TRequest, TResponse]) invoke(ctx context.Context, index int, request TRequest) (interface{}, error) {
+	if index == len(c.behaviors) {
+		return c.handler.Handle(ctx, request)
+	}
+
+	return c.behaviors[index].Handle(ctx, request, c.continuation(index, request))
+}
+
+// continuation is the next function passed to the behavior at index with request, it passes request, or its
+// replacement, to the next link
+func (c *pipelineCall[TRequest, TResponse]) continuation(index int, request TRequest) RequestHandlerFunc {
+	return func(ctx context.Context, replacement ...interface{}) (interface{}, error) {
+		if c.returned.Load() {
+			return nil, errContinuationAfterReturn
+		}
+		next, err := replaceRequest(request, replacement)
+		if err != nil {
+			return nil, err
+		}
+
+		return c.invoke(ctx, index+1, next)
+	}
+}
+
+// publishThroughBehaviors calls the notification behaviors in registration order, the last one continues with publish
+func publishThroughBehaviors(
+	ctx context.Context,
+	behaviors []NotificationPipelineBehavior,
+	notification interface{},
+	publish NotificationHandlerFunc,
+) error {
+	chain := publish
+	for i := len(behaviors) - 1; i >= 0; i-- {
+		behavior, next := behaviors[i], chain
+		chain = func(ctx context.Context) error {
+			return behavior.Handle(ctx, notification, next)
+		}
+	}
+
+	return chain(ctx)
+}
+
+// replaceRequest returns the replacement passed to a continuation, or the current request if there isn't any
+func replaceRequest[TRequest any](request TRequest, replacement []interface{}) (TRequest, error) {
+	switch len(replacement) {
+	case 0:
+		return request, nil
+	case 1:
+		replaced, ok := replacement[0].(TRequest)
+		if !ok {
+			return request, errors.Errorf(
+				"invalid replacement request %T, expected %s",
+				replacement[0],
+				reflect.TypeOf((*TRequest)(nil)).Elem(),
+			)
+		}
+		return replaced, nil
+	default:
+		return request, errors.Errorf("expected at most one replacement request, got %d", len(replacement))
+	}
+}
@@ -0,0 +1,324 @@
+package mediatr
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_Should_Pass_Replacements_Of_Concurrent_Calls_Of_Next(t *testing.T) {
+	defer cleanup()
+	handler := &collectingRequestHandler{}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](handler))
+	require.NoError(t, RegisterRequestPipelineBehaviors(&fanOutPipelineBehaviourTest{}, &slowPipelineBehaviourTest{}))
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "original"})
+
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"a", "b"}, handler.received())
+}
+
+func Test_Send_Should_Accept_Next_Called_With_Another_Context(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	require.NoError(t, RegisterRequestPipelineBehaviors(PipelineBehaviorFunc(detachingBehavior)))
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+}
+
+func Test_Send_Should_Use_Behavior_Registered_After_First_Send(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: " test "})
+	require.NoError(t, err)
+
+	require.NoError(t, RegisterRequestPipelineBehaviors(&NormalizerPipelineBehaviourTest{}))
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: " test "})
+
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+}
+
+func Test_Send_Should_Pass_Behavior_Request_To_Each_Call_Of_Next(t *testing.T) {
+	defer cleanup()
+	recorder := &RecorderPipelineBehaviourTest{}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	require.NoError(t, RegisterRequestPipelineBehaviors(&retryPipelineBehaviourTest{}, recorder))
+	request := &RequestTest{Data: "test"}
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+	assert.Same(t, request, recorder.request, "the retry should receive the request of the behavior, not the replacement of the first call")
+}
+
+func Test_Send_Should_Return_Error_If_Next_Is_Called_After_Behavior_Returned(t *testing.T) {
+	defer cleanup()
+	leaking := &leakingPipelineBehaviourTest{}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	require.NoError(t, RegisterRequestPipelineBehaviors(leaking))
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+	require.NoError(t, err)
+
+	_, err = leaking.next(context.Background())
+
+	assert.ErrorIs(t, err, errContinuationAfterReturn)
+}
+
+func Test_Send_Should_Reject_Stale_Continuations_Of_Concurrent_Calls(t *testing.T) {
+	defer cleanup()
+	behavior := &goroutinePipelineBehaviourTest{}
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	require.NoError(t, RegisterRequestPipelineBehaviors(behavior, forwardingBehavior[struct{}]{}))
+	sendConcurrently := func(wg *sync.WaitGroup) {
+		for i := 0; i < 50; i++ {
+			wg.Add(1)
+			go func(data string) {
+				defer wg.Done()
+				response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: data})
+				if assert.NoError(t, err) {
+					assert.Equal(t, data, response.Data, "the response should be the one of the sent request")
+				}
+			}(strconv.Itoa(i))
+		}
+	}
+	var wg sync.WaitGroup
+	sendConcurrently(&wg)
+	wg.Wait()
+
+	sendConcurrently(&wg)
+	for _, continuation := range behavior.escaped() {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := continuation.next(continuation.ctx)
+			assert.ErrorIs(t, err, errContinuationAfterReturn)
+		}()
+	}
+	wg.Wait()
+}
+
+func Test_Publish_Should_Run_Notification_Behaviors_In_Registration_Order(t *testing.T) {
+	defer cleanup()
+	var calls []string
+	require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+		calls = append(calls, "handler")
+		return nil
+	}))
+	require.NoError(t, RegisterNotificationPipelineBehaviors(
+		&namedNotificationBehaviourTest{name: "first", calls: &calls},
+		&tracingNotificationBehaviourTest{calls: &calls},
+	))
+
+	err := Publish(context.Background(), &NotificationTest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"first", "tracing", "handler"}, calls)
+}
+
+func Test_Publish_Should_Run_Notification_Behaviors_Without_Handlers(t *testing.T) {
+	defer cleanup()
+	var calls []string
+	require.NoError(t, RegisterNotificationPipelineBehaviors(&tracingNotificationBehaviourTest{calls: &calls}))
+
+	err := Publish(context.Background(), &NotificationTest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tracing"}, calls)
+}
+
+func Test_RegisterNotificationPipelineBehaviors_Should_Return_Error_If_Behavior_Already_Registered(t *testing.T) {
+	defer cleanup()
+	var calls []string
+	require.NoError(t, RegisterNotificationPipelineBehaviors(&tracingNotificationBehaviourTest{calls: &calls}))
+
+	err := RegisterNotificationPipelineBehaviors(
+		&namedNotificationBehaviourTest{name: "first", calls: &calls},
+		&tracingNotificationBehaviourTest{calls: &calls},
+	)
+
+	assert.EqualError(t, err, "notification behavior already registered")
+	assert.Len(t, loadRegistry().notificationBehaviors, 1, "no behavior should be registered on error")
+}
+
+func Test_ClearPipelineBehaviors_Should_Clear_Notification_Behaviors(t *testing.T) {
+	defer cleanup()
+	var calls []string
+	require.NoError(t, RegisterNotificationPipelineBehaviors(&tracingNotificationBehaviourTest{calls: &calls}))
+
+	ClearPipelineBehaviors()
+	err := Publish(context.Background(), &NotificationTest{})
+
+	require.NoError(t, err)
+	assert.Empty(t, calls)
+}
+
+func sendAllocs(t *testing.T, ctx context.Context, request *RequestTest) float64 {
+	return testing.AllocsPerRun(100, func() {
+		if _, err := Send[*RequestTest, *ResponseTest](ctx, request); err != nil {
+			t.Fatal(err)
+		}
+	})
+}
+
+// forwardingBehaviors returns 5 behaviors of distinct types, calling next
+func forwardingBehaviors() []PipelineBehavior {
+	return []PipelineBehavior{
+		forwardingBehavior[[1]struct{}]{},
+		forwardingBehavior[[2]struct{}]{},
+		forwardingBehavior[[3]struct{}]{},
+		forwardingBehavior[[4]struct{}]{},
+		forwardingBehavior[[5]struct{}]{},
+	}
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type forwardingBehavior[T any] struct {
+}
+
+func (c forwardingBehavior[T]) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	return next(ctx)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type retryPipelineBehaviourTest struct {
+}
+
+func (c *retryPipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	if _, err := next(ctx, &RequestTest{Data: "first"}); err != nil {
+		return nil, err
+	}
+
+	return next(ctx)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+// fanOutPipelineBehaviourTest calls next concurrently with two replacements
+type fanOutPipelineBehaviourTest struct {
+}
+
+func (c *fanOutPipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	var wg sync.WaitGroup
+	errs := make([]error, 2)
+	for i, data := range []string{"a", "b"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[i] = next(ctx, &RequestTest{Data: data})
+		}()
+	}
+	wg.Wait()
+
+	return &ResponseTest{}, errors.Join(errs...)
+}
+
+type slowPipelineBehaviourTest struct {
+}
+
+func (c *slowPipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	time.Sleep(10 * time.Millisecond)
+
+	return next(ctx)
+}
+
+type collectingRequestHandler struct {
+	mutex sync.Mutex
+	data  []string
+}
+
+func (c *collectingRequestHandler) Handle(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.data = append(c.data, request.Data)
+
+	return &ResponseTest{Data: request.Data}, nil
+}
+
+func (c *collectingRequestHandler) received() []string {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]string(nil), c.data...)
+}
+
+func detachingBehavior(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	return next(context.Background())
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+// goroutinePipelineBehaviourTest calls next in a goroutine, and keeps the continuations it received
+type goroutinePipelineBehaviourTest struct {
+	mutex         sync.Mutex
+	continuations []escapedContinuation
+}
+
+type escapedContinuation struct {
+	ctx  context.Context
+	next RequestHandlerFunc
+}
+
+func (c *goroutinePipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	c.mutex.Lock()
+	c.continuations = append(c.continuations, escapedContinuation{ctx: ctx, next: next})
+	c.mutex.Unlock()
+
+	type result struct {
+		response interface{}
+		err      error
+	}
+	done := make(chan result, 1)
+	go func() {
+		response, err := next(ctx)
+		done <- result{response: response, err: err}
+	}()
+	r := <-done
+
+	return r.response, r.err
+}
+
+func (c *goroutinePipelineBehaviourTest) escaped() []escapedContinuation {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return append([]escapedContinuation(nil), c.continuations...)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type leakingPipelineBehaviourTest struct {
+	next RequestHandlerFunc
+}
+
+func (c *leakingPipelineBehaviourTest) Handle(ctx context.Context, request interface{}, next RequestHandlerFunc) (interface{}, error) {
+	c.next = next
+
+	return next(ctx)
+}
+
+type namedNotificationBehaviourTest struct {
+	name  string
+	calls *[]string
+}
+
+func (c *namedNotificationBehaviourTest) Handle(ctx context.Context, notification interface{}, next NotificationHandlerFunc) error {
+	*c.calls = append(*c.calls, c.name)
+	return next(ctx)
+}
+
+type tracingNotificationBehaviourTest struct {
+	calls *[]string
+}
+
+func (c *tracingNotificationBehaviourTest) Handle(ctx context.Context, notification interface{}, next NotificationHandlerFunc) error {
+	*c.calls = append(*c.calls, "tracing")
+	return next(ctx)
+}
@@ -0,0 +1,6 @@
+//go:build race
+
+package mediatr
+
+// raceEnabled skips the allocation tests, the race detector makes sync.Pool drop pooled values
+const raceEnabled = true
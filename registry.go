@@ -0,0 +1,212 @@
+package mediatr
+
+import (
+	"context"
+	"maps"
+	"reflect"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/mehdihadeli/go-mediatr/internal/registryhook"
+	"github.com/pkg/errors"
+)
+
+// registrySnapshot is an immutable view of the registrations. Registrations and clears publish a new snapshot,
+// built from a copy of the current one, so that Send and Publish read one consistent view without locking.
+type registrySnapshot struct {
+	requests      map[reflect.Type]*handlerRegistration
+	notifications map[reflect.Type][]*handlerRegistration
+	behaviors     []PipelineBehavior
+
+	notificationBehaviors []NotificationPipelineBehavior
+}
+
+var (
+	currentRegistry atomic.Pointer[registrySnapshot]
+	emptyRegistry   = &registrySnapshot{}
+
+	// registryMutex serializes the updates of the registry, the reads don't take it
+	registryMutex sync.Mutex
+
+	// callObserver is the observer of the calls set by a test support package, nil if there isn't any
+	callObserver atomic.Pointer[registryhook.Observer]
+)
+
+func init() {
+	registryhook.Isolate = isolateRegistry
+	registryhook.Observe = observeCalls
+}
+
+// loadRegistry returns the current snapshot of the registrations
+func loadRegistry() *registrySnapshot {
+	if snapshot := currentRegistry.Load(); snapshot != nil {
+		return snapshot
+	}
+
+	return emptyRegistry
+}
+
+// updateRegistry applies update to a copy of the current snapshot and publishes the copy, or nothing if update fails
+func updateRegistry(update func(next *registrySnapshot) error) error {
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	next := loadRegistry().clone()
+	if err := update(next); err != nil {
+		return err
+	}
+	currentRegistry.Store(next)
+
+	return nil
+}
+
+func (s *registrySnapshot) clone() *registrySnapshot {
+	next := &registrySnapshot{
+		requests:      make(map[reflect.Type]*handlerRegistration, len(s.requests)),
+		notifications: make(map[reflect.Type][]*handlerRegistration, len(s.notifications)),
+		behaviors:     slices.Clone(s.behaviors),
+
+		notificationBehaviors: slices.Clone(s.notificationBehaviors),
+	}
+	maps.Copy(next.requests, s.requests)
+	maps.Copy(next.notifications, s.notifications)
+
+	return next
+}
+
+func (s *registrySnapshot) addRequestHandler(requestType reflect.Type, registration *handlerRegistration) error {
+	if _, exists := s.requests[requestType]; exists {
+		return errors.Errorf("handler already exists for type %s", requestType.String())
+	}
+	s.requests[requestType] = registration
+
+	return nil
+}
+
+func (s *registrySnapshot) addNotificationHandler(notificationType reflect.Type, registration *handlerRegistration) error {
+	// the handlers of the published snapshots are never appended to, they are read by concurrent Publish calls
+	s.notifications[notificationType] = append(slices.Clip(s.notifications[notificationType]), registration)
+
+	return nil
+}
+
+func (s *registrySnapshot) addPipelineBehavior(behavior PipelineBehavior) error {
+	for _, existing := range s.behaviors {
+		if sameBehavior(existing, behavior) {
+			return errors.New("behavior already registered")
+		}
+	}
+	s.behaviors = append(s.behaviors, behavior)
+
+	return nil
+}
+
+func (s *registrySnapshot) addNotificationBehavior(behavior NotificationPipelineBehavior) error {
+	for _, existing := range s.notificationBehaviors {
+		if reflect.TypeOf(existing) == reflect.TypeOf(behavior) {
+			return errors.New("notification behavior already registered")
+		}
+	}
+	s.notificationBehaviors = append(s.notificationBehaviors, behavior)
+
+	return nil
+}
+
+// requestRegistration returns the registration of a request type, consulting the handler resolver for unregistered types
+func (s *registrySnapshot) requestRegistration(ctx context.Context, requestType reflect.Type) (*handlerRegistration, error) {
+	if registration, ok := s.requests[requestType]; ok {
+		return registration, nil
+	}
+
+	registration, _, err := resolveRequestRegistration(ctx, requestType)
+
+	return registration, err
+}
+
+// notificationRegistrations returns the registrations of a notification type, consulting the handler resolver for unregistered types
+func (s *registrySnapshot) notificationRegistrations(ctx context.Context, notificationType reflect.Type) ([]*handlerRegistration, error) {
+	if registrations, ok := s.notifications[notificationType]; ok {
+		return registrations, nil
+	}
+
+	return resolveNotificationRegistrations(ctx, notificationType)
+}
+
+// Registrations is an opaque copy of the registrations of the mediator, taken by Snapshot and put back by Restore.
+type Registrations struct {
+	registry *registrySnapshot
+	modules  map[string]struct{}
+}
+
+// Snapshot returns a copy of the registrations: the request and notification handlers, the request and notification
+// pipeline behaviors, and the names of the installed modules. The registrations are immutable once registered,
+// so taking a snapshot doesn't copy them. The handler resolver and the executor are not part of the snapshot.
+//
+// Example:
+//
+//	snapshot := mediatr.Snapshot()
+//	defer mediatr.Restore(snapshot)
+//
+//	mediatr.ClearRequestRegistrations()
+//	err := mediatr.RegisterRequestHandler[*CreateProductCommand, *CreateProductCommandResponse](failingHandler)
+func Snapshot() *Registrations {
+	installMutex.Lock()
+	defer installMutex.Unlock()
+
+	return &Registrations{registry: loadRegistry(), modules: maps.Clone(installedModules)}
+}
+
+// Restore replaces the registrations with a snapshot, exactly as they were when it was taken, including the order
+// of the notification handlers and of the behaviors. A nil snapshot restores empty registrations.
+func Restore(snapshot *Registrations) {
+	if snapshot == nil {
+		snapshot = &Registrations{registry: emptyRegistry}
+	}
+
+	installMutex.Lock()
+	defer installMutex.Unlock()
+	registryMutex.Lock()
+	defer registryMutex.Unlock()
+
+	currentRegistry.Store(snapshot.registry)
+	installedModules = maps.Clone(snapshot.modules)
+	if installedModules == nil {
+		installedModules = map[string]struct{}{}
+	}
+}
+
+// isolateRegistry replaces the registrations, the installed modules and the handler resolver with empty ones,
+// until the returned function restores them
+func isolateRegistry() func() {
+	previous := Snapshot()
+	Restore(nil)
+
+	resolverMutex.Lock()
+	previousResolver := handlerResolver
+	handlerResolver = nil
+	resolverMutex.Unlock()
+
+	return func() {
+		Restore(previous)
+		SetHandlerResolver(previousResolver)
+	}
+}
+
+// observeCalls sets the observer of the calls, until the returned function restores the previous one
+func observeCalls(observer registryhook.Observer) func() {
+	previous := callObserver.Swap(&observer)
+
+	return func() {
+		callObserver.Store(previous)
+	}
+}
+
+// loadObserver returns the observer of the calls, or nil if there isn't any
+func loadObserver() registryhook.Observer {
+	if observer := callObserver.Load(); observer != nil {
+		return *observer
+	}
+
+	return nil
+}
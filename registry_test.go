@@ -0,0 +1,150 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Registry_Should_Not_Race_Under_Concurrent_Register_Clear_And_Dispatch(t *testing.T) {
+	defer cleanup()
+	const iterations = 200
+	var wg sync.WaitGroup
+	run := func(fn func()) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < iterations; i++ {
+				fn()
+			}
+		}()
+	}
+
+	run(func() {
+		_ = RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{})
+		ClearRequestRegistrations()
+	})
+	run(func() {
+		_ = RegisterNotificationHandler[*NotificationTest](&dispatchCountingHandler{})
+		ClearNotificationRegistrations()
+	})
+	run(func() {
+		_ = RegisterRequestPipelineBehaviors(forwardingBehaviors()...)
+		ClearPipelineBehaviors()
+	})
+	run(func() {
+		_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+		if err != nil {
+			assert.ErrorContains(t, err, "no handler for request *mediatr.RequestTest")
+		}
+	})
+	run(func() {
+		assert.NoError(t, Publish(context.Background(), &NotificationTest{}))
+	})
+	run(func() {
+		for request := range Describe().Requests() {
+			assert.Equal(t, "*mediatr.RequestTest", request.RequestType)
+		}
+	})
+	wg.Wait()
+}
+
+func Test_Registry_Snapshot_Should_Not_Change_After_Registration(t *testing.T) {
+	defer cleanup()
+	snapshot := loadRegistry()
+
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&dispatchCountingHandler{}))
+	require.NoError(t, RegisterRequestPipelineBehaviors(&PipelineBehaviourTest{}))
+
+	assert.Empty(t, snapshot.requests)
+	assert.Empty(t, snapshot.notifications)
+	assert.Empty(t, snapshot.behaviors)
+	assert.NotSame(t, snapshot, loadRegistry())
+}
+
+func Test_Registry_Should_Not_Share_Notification_Handlers_Between_Snapshots(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&dispatchCountingHandler{}))
+	snapshot := loadRegistry()
+
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&NotificationTestHandler4{}))
+
+	for _, registrations := range snapshot.notifications {
+		assert.Len(t, registrations, 1)
+	}
+	assert.Equal(t, 2, countNotificationHandlers(reflect.TypeOf(&NotificationTest{})))
+}
+
+func Test_RegisterRequestPipelineBehaviors_Should_Register_Nothing_If_A_Behavior_Is_Duplicate(t *testing.T) {
+	defer cleanup()
+
+	err := RegisterRequestPipelineBehaviors(&PipelineBehaviourTest{}, &PipelineBehaviourTest2{}, &PipelineBehaviourTest{})
+
+	assert.EqualError(t, err, "behavior already registered")
+	assert.Empty(t, loadRegistry().behaviors)
+}
+
+func Test_Restore_Should_Put_Back_Registrations_In_Order(t *testing.T) {
+	defer cleanup()
+	var calls []string
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	for _, name := range []string{"first", "second", "third"} {
+		require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+			calls = append(calls, name)
+			return nil
+		}))
+	}
+	require.NoError(t, RegisterRequestPipelineBehaviors(&NormalizerPipelineBehaviourTest{}, &RecorderPipelineBehaviourTest{}))
+	require.NoError(t, RegisterNotificationPipelineBehaviors(&tracingNotificationBehaviourTest{calls: &calls}))
+	snapshot := Snapshot()
+
+	ClearRequestRegistrations()
+	ClearNotificationRegistrations()
+	ClearPipelineBehaviors()
+	require.NoError(t, RegisterRequestHandlerFunc(func(ctx context.Context, request *RequestTest) (*ResponseTest, error) {
+		return &ResponseTest{Data: "tweaked"}, nil
+	}))
+	Restore(snapshot)
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: " test "})
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+	var behaviors []string
+	for _, behavior := range loadRegistry().behaviors {
+		behaviors = append(behaviors, BehaviorName(behavior))
+	}
+	assert.Equal(t, []string{"NormalizerPipelineBehaviourTest", "RecorderPipelineBehaviourTest"}, behaviors)
+	require.NoError(t, Publish(context.Background(), &NotificationTest{}))
+	assert.Equal(t, []string{"tracing", "first", "second", "third"}, calls)
+}
+
+func Test_Restore_Should_Put_Back_Installed_Modules(t *testing.T) {
+	defer cleanup()
+	noRegistrations := func(r Registrar) error { return nil }
+	require.NoError(t, Install(&testModule{name: "behaviours", register: noRegistrations}))
+	snapshot := Snapshot()
+	dependent := &testModule{name: "products", dependsOn: []string{"behaviours"}, register: noRegistrations}
+
+	Restore(nil)
+	err := Install(dependent)
+	require.EqualError(t, err, "module products depends on module behaviours, which is not installed")
+
+	Restore(snapshot)
+	assert.NoError(t, Install(dependent))
+}
+
+func Test_Restore_Should_Not_Be_Affected_By_Registrations_After_Snapshot(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&NotificationTestHandler{}))
+	snapshot := Snapshot()
+
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&NotificationTestHandler4{}))
+	Restore(snapshot)
+
+	assert.Len(t, loadRegistry().notifications[reflect.TypeOf(&NotificationTest{})], 1)
+}
@@ -0,0 +1,82 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"sync"
+)
+
+// HandlerResolver resolves handlers from an external source, such as a DI container. The mediator
+// consults it for the request and notification types that are not registered in its own registry.
+type HandlerResolver interface {
+	// ResolveRequestHandler returns the handler of a request type, it should implement RequestHandler[TRequest, TResponse].
+	// Returns false if the resolver doesn't know the request type.
+	ResolveRequestHandler(ctx context.Context, requestType reflect.Type) (interface{}, bool, error)
+
+	// ResolveNotificationHandlers returns the handlers of a notification type, each should implement
+	// NotificationHandler[TNotification]. Returns an empty slice if the resolver doesn't know the notification type.
+	ResolveNotificationHandlers(ctx context.Context, notificationType reflect.Type) ([]interface{}, error)
+}
+
+var (
+	handlerResolver HandlerResolver
+	resolverMutex   sync.RWMutex
+)
+
+// SetHandlerResolver sets the resolver consulted for unregistered request and notification types.
+// Pass nil to remove the current resolver.
+//
+// Example:
+//
+//	mediatr.SetHandlerResolver(myContainerResolver)
+func SetHandlerResolver(resolver HandlerResolver) {
+	resolverMutex.Lock()
+	defer resolverMutex.Unlock()
+
+	handlerResolver = resolver
+}
+
+func currentHandlerResolver() HandlerResolver {
+	resolverMutex.RLock()
+	defer resolverMutex.RUnlock()
+
+	return handlerResolver
+}
+
+// resolveRequestRegistration asks the resolver for the handler of an unregistered request type
+func resolveRequestRegistration(ctx context.Context, requestType reflect.Type) (*handlerRegistration, bool, error) {
+	resolver := currentHandlerResolver()
+	if resolver == nil {
+		return nil, false, nil
+	}
+
+	handler, ok, err := resolver.ResolveRequestHandler(ctx, requestType)
+	if err != nil {
+		return nil, false, &handlerConstructionError{err: err}
+	}
+	if !ok {
+		return nil, false, nil
+	}
+
+	return newInstanceRegistration(handler), true, nil
+}
+
+// resolveNotificationRegistrations asks the resolver for the handlers of an unregistered notification type
+func resolveNotificationRegistrations(ctx context.Context, notificationType reflect.Type) ([]*handlerRegistration, error) {
+	resolver := currentHandlerResolver()
+	if resolver == nil {
+		return nil, nil
+	}
+
+	handlers, err := resolver.ResolveNotificationHandlers(ctx, notificationType)
+	if err != nil {
+		return nil, &handlerConstructionError{err: err}
+	}
+
+	registrations := make([]*handlerRegistration, 0, len(handlers))
+	for _, handler := range handlers {
+		registrations = append(registrations, newInstanceRegistration(handler))
+	}
+
+	return registrations, nil
+}
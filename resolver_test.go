@@ -0,0 +1,88 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_Send_Should_Consult_Resolver_For_Unregistered_Request(t *testing.T) {
+	defer cleanup()
+	resolver := &mapHandlerResolver{requestHandlers: map[reflect.Type]interface{}{
+		reflect.TypeOf(&RequestTest{}): &echoRequestHandler{},
+	}}
+	SetHandlerResolver(resolver)
+	defer SetHandlerResolver(nil)
+
+	response, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{Data: "test"})
+	require.NoError(t, err)
+	assert.Equal(t, "test", response.Data)
+
+	_, err = Send[*RequestTest2, *ResponseTest2](context.Background(), &RequestTest2{})
+	assert.ErrorContains(t, err, "no handler for request *mediatr.RequestTest2")
+}
+
+func Test_Send_Should_Prefer_Registered_Handler_Over_Resolver(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&echoRequestHandler{}))
+	resolver := &mapHandlerResolver{err: errors.New("should not be called")}
+	SetHandlerResolver(resolver)
+	defer SetHandlerResolver(nil)
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+
+	require.NoError(t, err)
+}
+
+func Test_Send_Should_Return_ErrHandlerConstruction_If_Resolver_Fails(t *testing.T) {
+	defer cleanup()
+	SetHandlerResolver(&mapHandlerResolver{err: errors.New("missing dependency")})
+	defer SetHandlerResolver(nil)
+
+	_, err := Send[*RequestTest, *ResponseTest](context.Background(), &RequestTest{})
+
+	assert.ErrorIs(t, err, ErrHandlerConstruction)
+	assert.ErrorContains(t, err, "missing dependency")
+}
+
+func Test_Publish_Should_Consult_Resolver_For_Unregistered_Notification(t *testing.T) {
+	defer cleanup()
+	SetHandlerResolver(&mapHandlerResolver{notificationHandlers: map[reflect.Type][]interface{}{
+		reflect.TypeOf(&NotificationTest{}): {&NotificationTestHandler{}},
+	}})
+	defer SetHandlerResolver(nil)
+
+	notification := &NotificationTest{}
+	err := Publish(context.Background(), notification)
+
+	require.NoError(t, err)
+	assert.True(t, notification.Processed)
+}
+
+// /////////////////////////////////////////////////////////////////////////////////////////////
+type mapHandlerResolver struct {
+	requestHandlers      map[reflect.Type]interface{}
+	notificationHandlers map[reflect.Type][]interface{}
+	err                  error
+}
+
+func (r *mapHandlerResolver) ResolveRequestHandler(ctx context.Context, requestType reflect.Type) (interface{}, bool, error) {
+	if r.err != nil {
+		return nil, false, r.err
+	}
+	handler, ok := r.requestHandlers[requestType]
+
+	return handler, ok, nil
+}
+
+func (r *mapHandlerResolver) ResolveNotificationHandlers(ctx context.Context, notificationType reflect.Type) ([]interface{}, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	return r.notificationHandlers[notificationType], nil
+}
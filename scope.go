@@ -0,0 +1,136 @@
+package mediatr
+
+import (
+	"context"
+	"io"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Scope holds the handlers built by Scoped factories for a unit of work. Send and Publish begin a scope when their context doesn't have one, and close it
+// when they return, so nested Send and Publish calls made by handlers share the scope of the top-level call.
+type Scope struct {
+	mutex       sync.Mutex
+	instances   map[*handlerRegistration]*scopedInstance
+	disposables []io.Closer
+	closed      bool
+}
+
+// scopedInstance is the handler of a Scoped registration in a scope, its mutex serializes the construction of the
+// handler, so that the concurrent calls of a scope share a single handler
+type scopedInstance struct {
+	mutex   sync.Mutex
+	handler interface{}
+	built   bool
+}
+
+type scopeKey struct{}
+
+// NewScope begins a scope that spans every Send and Publish made with the returned context.
+// The caller is responsible for closing it.
+//
+// Example:
+//
+//	ctx, scope := mediatr.NewScope(ctx)
+//	defer scope.Close()
+func NewScope(ctx context.Context) (context.Context, *Scope) {
+	scope := &Scope{}
+
+	return context.WithValue(ctx, scopeKey{}, scope), scope
+}
+
+// ScopeFromContext returns the scope of the call currently being handled.
+func ScopeFromContext(ctx context.Context) (*Scope, bool) {
+	scope, ok := ctx.Value(scopeKey{}).(*Scope)
+
+	return scope, ok && scope != nil
+}
+
+// Close disposes the scoped handlers implementing io.Closer, in reverse creation order.
+// Returns the first error encountered, if any.
+func (s *Scope) Close() error {
+	s.mutex.Lock()
+	disposables := s.disposables
+	s.disposables = nil
+	s.instances = nil
+	s.closed = true
+	s.mutex.Unlock()
+
+	var firstErr error
+	for i := len(disposables) - 1; i >= 0; i-- {
+		if err := disposables[i].Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// resolve returns the handler of a Scoped registration in the scope, building it on the first call. The handler is
+// built without holding the mutex of the scope, so that a factory can make nested calls resolving other handlers of
+// the scope.
+func (s *Scope) resolve(ctx context.Context, registration *handlerRegistration) (interface{}, error) {
+	s.mutex.Lock()
+	if s.closed {
+		s.mutex.Unlock()
+		return nil, errors.New("scope is closed")
+	}
+	instance, ok := s.instances[registration]
+	if !ok {
+		if s.instances == nil {
+			s.instances = map[*handlerRegistration]*scopedInstance{}
+		}
+		instance = &scopedInstance{}
+		s.instances[registration] = instance
+	}
+	s.mutex.Unlock()
+
+	instance.mutex.Lock()
+	defer instance.mutex.Unlock()
+
+	if instance.built {
+		return instance.handler, nil
+	}
+
+	handler, err := registration.build(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if closer, ok := handler.(io.Closer); ok {
+		if err := s.track(closer); err != nil {
+			_ = closer.Close()
+			return nil, err
+		}
+	}
+	instance.handler, instance.built = handler, true
+
+	return handler, nil
+}
+
+func (s *Scope) track(closer io.Closer) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return errors.New("scope is closed")
+	}
+	s.disposables = append(s.disposables, closer)
+
+	return nil
+}
+
+// closeScope closes the scope begun by a top-level call. Calls made with a context that already has a scope join it
+// and don't close it.
+func (c *callContext) closeScope() error {
+	if !c.ownsScope {
+		return nil
+	}
+
+	return c.scope.Close()
+}
+
+// detachScope hides the scope of ctx, so that calls made with the returned context begin their own scope.
+func detachScope(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scopeKey{}, (*Scope)(nil))
+}
@@ -0,0 +1,287 @@
+package mediatr
+
+import (
+	"context"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// ValidationOption declares a contract checked by Validate.
+type ValidationOption func(*validationOptions)
+
+type validationOptions struct {
+	requests                     []requestContract
+	notifications                []notificationContract
+	rejectUnhandledNotifications bool
+}
+
+// requestContract is a request type a service must handle, with the response type its handler must return
+type requestContract struct {
+	requestType  reflect.Type
+	responseType reflect.Type
+	accepts      func(handler interface{}) bool
+}
+
+// notificationContract is a notification type a service must publish
+type notificationContract struct {
+	notificationType reflect.Type
+	accepts          func(handler interface{}) bool
+}
+
+// ValidationError is returned by Validate, with a problem for each broken contract.
+type ValidationError struct {
+	Problems []error
+}
+
+// Error lists the problems, one per line.
+func (e *ValidationError) Error() string {
+	var builder strings.Builder
+	builder.WriteString("mediatr registry validation failed:")
+	for _, problem := range e.Problems {
+		builder.WriteString("\n- ")
+		builder.WriteString(problem.Error())
+	}
+
+	return builder.String()
+}
+
+// Unwrap returns the problems, so errors.Is and errors.As match any of them.
+func (e *ValidationError) Unwrap() []error {
+	return e.Problems
+}
+
+// RequireRequest declares a request type that must have exactly one handler, responding with TResponse.
+func RequireRequest[TRequest any, TResponse any]() ValidationOption {
+	return func(o *validationOptions) {
+		o.requests = append(o.requests, requestContract{
+			requestType:  reflect.TypeOf((*TRequest)(nil)).Elem(),
+			responseType: reflect.TypeOf((*TResponse)(nil)).Elem(),
+			accepts: func(handler interface{}) bool {
+				_, typed := handler.(RequestHandler[TRequest, TResponse])
+				_, untyped := handler.(untypedRequestHandler)
+				return typed || untyped
+			},
+		})
+	}
+}
+
+// RequireNotification declares a notification type that is published by the service.
+// Without RejectUnhandledNotifications, a notification without handlers is valid.
+func RequireNotification[TNotification any]() ValidationOption {
+	return func(o *validationOptions) {
+		o.notifications = append(o.notifications, notificationContract{
+			notificationType: reflect.TypeOf((*TNotification)(nil)).Elem(),
+			accepts: func(handler interface{}) bool {
+				_, typed := handler.(NotificationHandler[TNotification])
+				_, untyped := handler.(untypedNotificationHandler)
+				return typed || untyped
+			},
+		})
+	}
+}
+
+// RejectUnhandledNotifications reports the notifications declared with RequireNotification that have no handlers.
+func RejectUnhandledNotifications() ValidationOption {
+	return func(o *validationOptions) {
+		o.rejectUnhandledNotifications = true
+	}
+}
+
+// Validate checks the registrations against the declared contracts: every request declared with RequireRequest
+// must have one handler with the declared response type, and with RejectUnhandledNotifications, every notification
+// declared with RequireNotification must have a handler. Types without registration are looked up in the
+// HandlerResolver, with a background context. Without contracts, the registered handlers are checked
+// against the types they are registered for.
+// Returns a *ValidationError listing all the problems, or nil.
+//
+// Example:
+//
+//	err := mediatr.Validate(
+//	    mediatr.RequireRequest[*CreateProductCommand, *CreateProductCommandResponse](),
+//	    mediatr.RequireRequest[*GetProductByIdQuery, *GetProductByIdQueryResponse](),
+//	    mediatr.RequireNotification[*ProductCreatedEvent](),
+//	    mediatr.RejectUnhandledNotifications(),
+//	)
+func Validate(opts ...ValidationOption) error {
+	options := validationOptions{}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx := context.Background()
+	var problems []error
+
+	if len(options.requests) == 0 && len(options.notifications) == 0 {
+		problems = validateRegistrations()
+	}
+
+	for _, contract := range options.requests {
+		if err := validateRequest(ctx, contract); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	for _, contract := range options.notifications {
+		problems = append(problems, validateNotification(ctx, contract, options.rejectUnhandledNotifications)...)
+	}
+
+	if len(problems) > 0 {
+		return &ValidationError{Problems: problems}
+	}
+
+	return nil
+}
+
+func validateRequest(ctx context.Context, contract requestContract) error {
+	registration, err := loadRequestRegistration(ctx, contract.requestType)
+	if err != nil {
+		return errors.Wrapf(err, "handler of request %s can't be resolved", contract.requestType)
+	}
+	if registration == nil {
+		return errors.Errorf("no handler for request %s", contract.requestType)
+	}
+
+	if registration.responseType != nil && registration.responseType != contract.responseType {
+		return errors.Errorf(
+			"handler of request %s responds with %s, expected %s",
+			contract.requestType,
+			registration.responseType,
+			contract.responseType,
+		)
+	}
+
+	if registration.factory == nil && !contract.accepts(registration.instance) {
+		return errors.Errorf(
+			"handler %T of request %s doesn't implement RequestHandler[%s, %s]",
+			registration.instance,
+			contract.requestType,
+			contract.requestType,
+			contract.responseType,
+		)
+	}
+
+	return nil
+}
+
+func validateNotification(ctx context.Context, contract notificationContract, rejectUnhandled bool) []error {
+	registrations, err := loadNotificationRegistrations(ctx, contract.notificationType)
+	if err != nil {
+		return []error{errors.Wrapf(err, "handlers of notification %s can't be resolved", contract.notificationType)}
+	}
+	if len(registrations) == 0 && rejectUnhandled {
+		return []error{errors.Errorf("no handler for notification %s", contract.notificationType)}
+	}
+
+	var problems []error
+	for _, registration := range registrations {
+		if registration.factory == nil && !contract.accepts(registration.instance) {
+			problems = append(problems, errors.Errorf(
+				"handler %T of notification %s doesn't implement NotificationHandler[%s]",
+				registration.instance,
+				contract.notificationType,
+				contract.notificationType,
+			))
+		}
+	}
+
+	return problems
+}
+
+// validateRegistrations checks the registered handlers, when no contract is declared: each handler, or the handler
+// type declared by each factory, must handle the type it is registered for. The factories are not invoked.
+func validateRegistrations() []error {
+	var problems []error
+
+	snapshot := loadRegistry()
+
+	for requestType, registration := range snapshot.requests {
+		if err := validateRegistration(registration, "request", requestType, registration.responseType); err != nil {
+			problems = append(problems, err)
+		}
+	}
+
+	for notificationType, registrations := range snapshot.notifications {
+		for _, registration := range registrations {
+			if err := validateRegistration(registration, "notification", notificationType, nil); err != nil {
+				problems = append(problems, err)
+			}
+		}
+	}
+
+	slices.SortFunc(problems, func(a, b error) int {
+		return strings.Compare(a.Error(), b.Error())
+	})
+
+	return problems
+}
+
+// validateRegistration checks that the handler of a registration handles messageType, responding with responseType
+// for a request handler. A nil responseType is a notification handler.
+func validateRegistration(registration *handlerRegistration, kind string, messageType reflect.Type, responseType reflect.Type) error {
+	var handlerName string
+	var handles bool
+
+	switch handler := registration.instance.(type) {
+	case nil:
+		if registration.factory == nil {
+			return errors.Errorf("nil handler for %s %s", kind, messageType)
+		}
+		factoryType := reflect.TypeOf(registration.source)
+		if factoryType == nil || factoryType.Kind() != reflect.Func || factoryType.NumOut() == 0 {
+			return nil
+		}
+		handlerName, handles = factoryType.Out(0).String(), handlesMessage(factoryType.Out(0), messageType, responseType)
+	case *methodHandler:
+		handlerName, handles = handler.handlerName(), handler.requestType == messageType && handler.responseType == responseType
+	default:
+		handlerName, handles = reflect.TypeOf(handler).String(), handlesMessage(reflect.TypeOf(handler), messageType, responseType)
+	}
+	if handles {
+		return nil
+	}
+
+	if responseType == nil {
+		return errors.Errorf("handler %s of notification %s doesn't implement NotificationHandler[%s]", handlerName, messageType, messageType)
+	}
+
+	return errors.Errorf(
+		"handler %s of request %s doesn't implement RequestHandler[%s, %s]",
+		handlerName,
+		messageType,
+		messageType,
+		responseType,
+	)
+}
+
+// handlesMessage returns whether the Handle method of handlerType has the signature of a handler of messageType,
+// responding with responseType for a request handler
+func handlesMessage(handlerType reflect.Type, messageType reflect.Type, responseType reflect.Type) bool {
+	method, ok := handlerType.MethodByName("Handle")
+	if !ok {
+		return false
+	}
+
+	methodType := method.Type
+	params := make([]reflect.Type, 0, methodType.NumIn())
+	for i := 0; i < methodType.NumIn(); i++ {
+		params = append(params, methodType.In(i))
+	}
+	if handlerType.Kind() != reflect.Interface {
+		// the method of a concrete type takes its receiver first
+		params = params[1:]
+	}
+	results := make([]reflect.Type, 0, methodType.NumOut())
+	for i := 0; i < methodType.NumOut(); i++ {
+		results = append(results, methodType.Out(i))
+	}
+
+	expectedResults := []reflect.Type{errorType}
+	if responseType != nil {
+		expectedResults = []reflect.Type{responseType, errorType}
+	}
+
+	return slices.Equal(params, []reflect.Type{contextType, messageType}) && slices.Equal(results, expectedResults)
+}
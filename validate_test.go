@@ -0,0 +1,128 @@
+package mediatr
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unhandledCommand is a request without handler
+type unhandledCommand struct{}
+
+func Test_Validate_Should_Pass_When_Contracts_Are_Registered(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](&RequestTestHandler{}))
+	require.NoError(t, RegisterRequestHandlerFactory(func() RequestHandler[*RequestTest2, *ResponseTest2] {
+		return &RequestTestHandler2{}
+	}))
+	require.NoError(t, RegisterNotificationHandler[*NotificationTest](&NotificationTestHandler{}))
+
+	err := Validate(
+		RequireRequest[*RequestTest, *ResponseTest](),
+		RequireRequest[*RequestTest2, *ResponseTest2](),
+		RequireNotification[*NotificationTest](),
+		RejectUnhandledNotifications(),
+	)
+
+	assert.NoError(t, err)
+}
+
+func Test_Validate_Should_Report_All_Broken_Contracts(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterHandlersFrom(&conventionHandlers{}))
+
+	err := Validate(
+		RequireRequest[*RequestTest, *ResponseTest](),
+		RequireRequest[*RequestTest2, *ResponseTest](),
+		RequireRequest[*unhandledCommand, Unit](),
+		RequireNotification[*NotificationTest](),
+		RequireNotification[*NotificationTest2](),
+		RejectUnhandledNotifications(),
+	)
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.EqualError(t, err, `mediatr registry validation failed:
+- handler of request *mediatr.RequestTest2 responds with *mediatr.ResponseTest2, expected *mediatr.ResponseTest
+- no handler for request *mediatr.unhandledCommand
+- no handler for notification *mediatr.NotificationTest2`)
+}
+
+func Test_Validate_Should_Accept_Unhandled_Notifications_By_Default(t *testing.T) {
+	defer cleanup()
+
+	err := Validate(RequireNotification[*NotificationTest]())
+
+	assert.NoError(t, err)
+}
+
+func Test_Validate_Should_Check_Handlers_Of_Resolver(t *testing.T) {
+	defer cleanup()
+	SetHandlerResolver(&mapHandlerResolver{requestHandlers: map[reflect.Type]interface{}{
+		reflect.TypeOf(&RequestTest{}):  &echoRequestHandler{},
+		reflect.TypeOf(&RequestTest2{}): &echoRequestHandler{},
+	}})
+	defer SetHandlerResolver(nil)
+
+	err := Validate(RequireRequest[*RequestTest, *ResponseTest](), RequireRequest[*RequestTest2, *ResponseTest2]())
+
+	assert.EqualError(t, err, `mediatr registry validation failed:
+- handler *mediatr.echoRequestHandler of request *mediatr.RequestTest2 doesn't implement RequestHandler[*mediatr.RequestTest2, *mediatr.ResponseTest2]`)
+}
+
+func Test_Validate_Should_Report_Resolver_Errors(t *testing.T) {
+	defer cleanup()
+	SetHandlerResolver(&mapHandlerResolver{err: errors.New("missing dependency")})
+	defer SetHandlerResolver(nil)
+
+	err := Validate(RequireRequest[*RequestTest, *ResponseTest]())
+
+	assert.ErrorIs(t, err, ErrHandlerConstruction)
+	assert.ErrorContains(t, err, "handler of request *mediatr.RequestTest can't be resolved: handler construction failed: missing dependency")
+}
+
+func Test_Validate_Should_Check_Registered_Handlers_Without_Contracts(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterRequestHandler[*RequestTest, *ResponseTest](nil))
+	require.NoError(t, RegisterNotificationHandlerFunc(func(ctx context.Context, notification *NotificationTest) error {
+		return nil
+	}))
+
+	err := Validate()
+
+	assert.EqualError(t, err, `mediatr registry validation failed:
+- nil handler for request *mediatr.RequestTest`)
+}
+
+func Test_Validate_Should_Report_Registered_Handlers_Of_Other_Types(t *testing.T) {
+	defer cleanup()
+	require.NoError(t, RegisterHandlersFrom(&conventionHandlers{}))
+	require.NoError(t, RegisterNotificationHandlerFactory(func() NotificationHandler[*NotificationTest2] {
+		return &NotificationTestHandler2{}
+	}))
+	// the registrations are made for the wrong types, as a reflection based registration could
+	err := updateRegistry(func(next *registrySnapshot) error {
+		requestHandler := withResponseType[*ResponseTest](newInstanceRegistration(&RequestTestHandler{}))
+		if err := next.addRequestHandler(reflect.TypeOf(unhandledCommand{}), requestHandler); err != nil {
+			return err
+		}
+		var factory NotificationHandlerFactory[*NotificationTest] = func() NotificationHandler[*NotificationTest] {
+			return &NotificationTestHandler{}
+		}
+		notificationHandler := newFactoryRegistration(factory, func(context.Context) (interface{}, error) {
+			return factory(), nil
+		}, nil)
+		return next.addNotificationHandler(reflect.TypeOf(&NotificationTest2{}), notificationHandler)
+	})
+	require.NoError(t, err)
+
+	err = Validate()
+
+	assert.EqualError(t, err, `mediatr registry validation failed:
+- handler *mediatr.RequestTestHandler of request mediatr.unhandledCommand doesn't implement RequestHandler[mediatr.unhandledCommand, *mediatr.ResponseTest]
+- handler mediatr.NotificationHandler[*github.com/mehdihadeli/go-mediatr.NotificationTest] of notification *mediatr.NotificationTest2 doesn't implement NotificationHandler[*mediatr.NotificationTest2]`)
+}